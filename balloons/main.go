@@ -9,13 +9,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/dikaeinstein/games-with-go/color"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-type rgba struct {
-	r, g, b byte
-}
-
 type pos struct {
 	x, y float32
 }
@@ -132,11 +129,10 @@ func main() {
 	defer tex.Destroy()
 
 	// cloudNoise := noise.MakeNoise(noise.FBM, winWidth, winHeight, .009, 0.5, 3, 3)
-	// cloudGradient := getGradient(rgba{0, 0, 255}, rgba{255, 255, 255})
-	// scale := noise.CalcScale(255.0)
-	// cloudNoise.Rescale(scale)
+	// cloudGradient := color.Even(color.RGB{R: 0, G: 0, B: 255}, color.RGB{R: 255, G: 255, B: 255}).Bake(256)
+	// cloudNoise.Normalize(0, 255)
 	// cloudPixels := make([]byte, winWidth*winHeight*4)
-	// drawNoise(cloudNoise, cloudGradient, cloudPixels)
+	// drawNoise(cloudNoise.Values, cloudGradient, cloudPixels)
 	// cloudTexture := texture{cloudPixels, winWidth, winHeight, winWidth * 4}
 
 	pixels := make([]byte, winWidth*winHeight*4)
@@ -228,52 +224,14 @@ func loadBalloons(imgs []image.Image) []*texture {
 	return textures
 }
 
-func setPixel(x, y int, c rgba, pixels []byte) {
+func setPixel(x, y int, c color.RGB, pixels []byte) {
 	index := (y*winWidth + x) * 4
 
 	if index < len(pixels)-4 && index >= 0 {
-		pixels[index] = c.r
-		pixels[index+1] = c.g
-		pixels[index+2] = c.b
-	}
-}
-
-func lerp(b1, b2 byte, pct float32) byte {
-	return uint8(float32(b1) + pct*(float32(b2)-float32(b1)))
-}
-
-func colorLerp(c1, c2 rgba, pct float32) rgba {
-	return rgba{
-		lerp(c1.r, c2.r, pct),
-		lerp(c1.g, c2.g, pct),
-		lerp(c1.b, c2.b, pct),
-	}
-}
-
-func getGradient(c1, c2 rgba) []rgba {
-	result := make([]rgba, 256)
-
-	for i := range result {
-		pct := float32(i) / float32(255)
-		result[i] = colorLerp(c1, c2, pct)
+		pixels[index] = c.R
+		pixels[index+1] = c.G
+		pixels[index+2] = c.B
 	}
-
-	return result
-}
-
-func getDualGradient(c1, c2, c3, c4 rgba) []rgba {
-	result := make([]rgba, 256)
-
-	for i := range result {
-		pct := float32(i) / float32(255)
-		if pct < 0.5 {
-			result[i] = colorLerp(c1, c2, pct*float32(2))
-		} else {
-			result[i] = colorLerp(c3, c4, pct*float32(1.5)-float32(0.5))
-		}
-	}
-
-	return result
 }
 
 // clamp ensure v is within this interval or boundary
@@ -288,12 +246,12 @@ func clamp(min, max, v int) int {
 }
 
 // drawNoise draws noise to the pixels buffer
-func drawNoise(noise []float32, gradient []rgba, pixels []byte) {
+func drawNoise(noise []float32, gradient color.LUT, pixels []byte) {
 	for i := range noise {
 		c := gradient[clamp(0, 255, int(noise[i]))]
 		p := i * 4
-		pixels[p] = c.r
-		pixels[p+1] = c.g
-		pixels[p+2] = c.b
+		pixels[p] = c.R
+		pixels[p+1] = c.G
+		pixels[p+2] = c.B
 	}
 }
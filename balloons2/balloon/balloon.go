@@ -1,9 +1,9 @@
 package balloon
 
 import (
-	"math"
 	"time"
 
+	"github.com/dikaeinstein/games-with-go/geometry"
 	"github.com/dikaeinstein/games-with-go/vector"
 	"github.com/veandco/go-sdl2/sdl"
 )
@@ -103,11 +103,8 @@ func UpdateBalloons(balloons []*Balloon, elapsedTime float32,
 		}
 
 		if !balloonClicked && !previous.LeftButton && current.LeftButton {
-			x, y, r := b.Circle()
-			dx := float32(current.X) - x
-			dy := float32(current.Y) - y
-			dist := float32(math.Sqrt(float64(dx*dx + dy*dy)))
-			if dist < r {
+			mouse := vector.Vec2{X: float32(current.X), Y: float32(current.Y)}
+			if b.Circle().Contains(mouse) {
 				balloonClicked = true
 				sdl.ClearQueuedAudio(audioState.DeviceID)
 				sdl.QueueAudio(audioState.DeviceID, audioState.ExplosionBytes)
@@ -119,7 +116,7 @@ func UpdateBalloons(balloons []*Balloon, elapsedTime float32,
 
 		// compute the new position for the ballon based on its current postion,
 		// velocity and the elapsedTime for the previous frame
-		p := vector.Add(b.pos, vector.Multiply(b.velocity, elapsedTime))
+		p := b.pos.Add(b.velocity.Scale(elapsedTime))
 		if p.X < 0 || p.X > float32(w) {
 			b.velocity.X = -b.velocity.X
 		}
@@ -130,7 +127,7 @@ func UpdateBalloons(balloons []*Balloon, elapsedTime float32,
 			b.velocity.Z = -b.velocity.Z
 		}
 
-		b.pos = vector.Add(b.pos, vector.Multiply(b.velocity, elapsedTime))
+		b.pos = b.pos.Add(b.velocity.Scale(elapsedTime))
 	}
 
 	if balloonExploaded {
@@ -151,13 +148,12 @@ func (b *Balloon) Scale() float32 {
 	return (b.pos.Z/200 + 1) / 2
 }
 
-func (b *Balloon) Circle() (x, y, r float32) {
+func (b *Balloon) Circle() geometry.Circle {
 	scale := b.Scale()
-	x = b.pos.X
-	y = b.pos.Y - 30*scale
-	r = (float32(b.w) / 2) * scale
-
-	return x, y, r
+	return geometry.Circle{
+		Center: vector.Vec2{X: b.pos.X, Y: b.pos.Y - 30*scale},
+		Radius: (float32(b.w) / 2) * scale,
+	}
 }
 
 type Slice []*Balloon
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image"
 	"image/png"
@@ -13,20 +14,40 @@ import (
 	"time"
 
 	"github.com/dikaeinstein/games-with-go/balloons2/balloon"
+	"github.com/dikaeinstein/games-with-go/color"
+	"github.com/dikaeinstein/games-with-go/dither"
 	"github.com/dikaeinstein/games-with-go/noise"
 	"github.com/dikaeinstein/games-with-go/vector"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-type rgba struct {
-	r, g, b byte
-}
-
 const winWidth = 800
 const winHeight = 600
 const winDepth = 100
 
 func main() {
+	palette := flag.String("palette", "", "cloud gradient palette file (.ggr, .json or .png)")
+	ditherName := flag.String("dither", "bluenoise", "cloud dithering: none, bayer, bluenoise or floyd-steinberg")
+	flag.Parse()
+
+	method, err := dither.ParseMethod(*ditherName)
+	if err != nil {
+		fmt.Println("Could not parse dithering method:", err)
+		return
+	}
+
+	// blend in Oklab so the sky does not turn gray between blue and white
+	sky := color.Even(color.RGB{R: 0, G: 0, B: 255}, color.RGB{R: 255, G: 255, B: 255})
+	sky.Space = color.OKLAB
+	if *palette != "" {
+		g, err := color.LoadGradient(*palette)
+		if err != nil {
+			fmt.Println("Could not load palette:", err)
+			return
+		}
+		sky = g
+	}
+
 	sdl.LogSetAllPriority(sdl.LOG_PRIORITY_VERBOSE)
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		panic(err)
@@ -59,12 +80,14 @@ func main() {
 
 	sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "1")
 
-	cloudNoise := noise.MakeNoise(noise.FBM, winWidth, winHeight, .009, 0.5, 3, 3)
-	cloudGradient := getGradient(rgba{0, 0, 255}, rgba{255, 255, 255})
-	scale := noise.CalcScale(255.0)
-	cloudNoise.Rescale(scale)
+	// the clouds wrap around the window so they can scroll forever
+	cloudParams := noise.DefaultParams(noise.FBM, .009, 0.5, 3, 3)
+	cloudParams.TileW, cloudParams.TileH = winWidth, winHeight
+	cloudNoise := noise.MakeField(noise.FBM, winWidth, winHeight, cloudParams)
+	cloudGradient := sky.Bake(256)
+	cloudNoise.Normalize(0, 1)
 	cloudPixels := make([]byte, winWidth*winHeight*4)
-	drawNoise(cloudNoise, cloudGradient, cloudPixels)
+	drawNoise(cloudNoise.Values, cloudGradient, method, cloudPixels)
 	cloudTexture := pixelsToTexture(renderer, cloudPixels, winWidth, winHeight)
 
 	imgs := loadImages("images", "balloon_")
@@ -73,6 +96,7 @@ func main() {
 	currentMouseState := balloon.GetMouseState()
 	previousMouseState := currentMouseState
 	var elapsedTime float32
+	var cloudOffset float32
 	running := true
 	for running {
 		frameStart := time.Now()
@@ -99,7 +123,14 @@ func main() {
 		// 	fmt.Println("left click")
 		// }
 
-		renderer.Copy(cloudTexture, nil, nil)
+		// draw the clouds twice, side by side, to scroll them
+		cloudOffset += 0.02 * elapsedTime
+		if cloudOffset >= winWidth {
+			cloudOffset -= winWidth
+		}
+		cloudX := int32(cloudOffset)
+		renderer.Copy(cloudTexture, nil, &sdl.Rect{X: -cloudX, Y: 0, W: winWidth, H: winHeight})
+		renderer.Copy(cloudTexture, nil, &sdl.Rect{X: winWidth - cloudX, Y: 0, W: winWidth, H: winHeight})
 
 		balloon.UpdateBalloons(balloons, elapsedTime, currentMouseState,
 			previousMouseState, audioState, winWidth, winHeight, winDepth)
@@ -225,62 +256,7 @@ func pixelsToTexture(renderer *sdl.Renderer, pixels []byte, w, h int) *sdl.Textu
 	return tex
 }
 
-func lerp(b1, b2 byte, pct float32) byte {
-	return uint8(float32(b1) + pct*(float32(b2)-float32(b1)))
-}
-
-func colorLerp(c1, c2 rgba, pct float32) rgba {
-	return rgba{
-		lerp(c1.r, c2.r, pct),
-		lerp(c1.g, c2.g, pct),
-		lerp(c1.b, c2.b, pct),
-	}
-}
-
-func getGradient(c1, c2 rgba) []rgba {
-	result := make([]rgba, 256)
-
-	for i := range result {
-		pct := float32(i) / float32(255)
-		result[i] = colorLerp(c1, c2, pct)
-	}
-
-	return result
-}
-
-func getDualGradient(c1, c2, c3, c4 rgba) []rgba {
-	result := make([]rgba, 256)
-
-	for i := range result {
-		pct := float32(i) / float32(255)
-		if pct < 0.5 {
-			result[i] = colorLerp(c1, c2, pct*float32(2))
-		} else {
-			result[i] = colorLerp(c3, c4, pct*float32(1.5)-float32(0.5))
-		}
-	}
-
-	return result
-}
-
-// clamp ensure v is within this interval or boundary
-func clamp(min, max, v int) int {
-	if v < min {
-		min = v
-	} else if v > max {
-		max = v
-	}
-
-	return v
-}
-
-// drawNoise draws noise to the pixels buffer
-func drawNoise(noise []float32, gradient []rgba, pixels []byte) {
-	for i := range noise {
-		c := gradient[clamp(0, 255, int(noise[i]))]
-		p := i * 4
-		pixels[p] = c.r
-		pixels[p+1] = c.g
-		pixels[p+2] = c.b
-	}
+// drawNoise draws noise in [0,1] to the pixels buffer
+func drawNoise(noise []float32, gradient color.LUT, method dither.Method, pixels []byte) {
+	dither.Field(noise, winWidth, gradient, method, pixels)
 }
@@ -0,0 +1,236 @@
+// Command noisegen renders a noise field to image files without needing a
+// display, so textures can be generated and compared from scripts and CI.
+//
+// Usage:
+//
+//	noisegen -type ridged -freq 0.005 -octaves 6 -seed 7 -size 512x512 \
+//		-gradient "0:#00004b,0.5:#50a0f4,1:#ffffff" -o ridged.png
+//
+// The noise is normalized to [0,1] before it is written. Besides the
+// colored PNG it can write a 16-bit grayscale heightmap PNG (-heightmap)
+// and the normalized values as little endian float32 rows (-raw).
+//
+// The colored PNG can be dithered (-dither) to hide the banding of the
+// gradient, and reduced to a retro palette (-retro) for pixel art.
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"image"
+	imgcolor "image/color"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dikaeinstein/games-with-go/color"
+	"github.com/dikaeinstein/games-with-go/dither"
+	"github.com/dikaeinstein/games-with-go/noise"
+	"github.com/dikaeinstein/games-with-go/simplex"
+)
+
+var noiseTypes = map[string]noise.Type{
+	"fbm":        noise.FBM,
+	"turbulence": noise.TURBULENCE,
+	"ridged":     noise.RIDGED,
+	"hybrid":     noise.HYBRID,
+	"cellular":   noise.CELLULAR,
+}
+
+var retroPalettes = map[string][]color.RGB{
+	"pico8":   dither.PICO8,
+	"gameboy": dither.GAMEBOY,
+}
+
+var metrics = map[string]noise.Metric{
+	"euclidean": noise.EUCLIDEAN,
+	"manhattan": noise.MANHATTAN,
+	"chebyshev": noise.CHEBYSHEV,
+}
+
+var features = map[string]noise.Feature{
+	"f1":    noise.F1,
+	"f2":    noise.F2,
+	"f2-f1": noise.F2MINUSF1,
+}
+
+func main() {
+	typeName := flag.String("type", "fbm", "noise type: fbm, turbulence, ridged, hybrid or cellular")
+	frequency := flag.Float64("freq", 0.01, "base frequency")
+	lacunarity := flag.Float64("lacunarity", 2, "frequency multiplier between octaves")
+	gain := flag.Float64("gain", 0.5, "amplitude multiplier between octaves")
+	octaves := flag.Int("octaves", 3, "number of octaves")
+	offset := flag.Float64("offset", 0, "offset of the ridged and hybrid types, 1 and 0.7 when unset")
+	hurst := flag.Float64("H", 0, "fractal increment of the ridged and hybrid types, 1 and 0.25 when unset")
+	seed := flag.Int64("seed", 0, "noise seed, 0 uses the default permutation table")
+	size := flag.String("size", "800x600", "image size as WIDTHxHEIGHT")
+	tile := flag.Bool("tile", false, "make the noise wrap around both axes")
+	metric := flag.String("metric", "euclidean", "cellular distance: euclidean, manhattan or chebyshev")
+	feature := flag.String("feature", "f1", "cellular feature: f1, f2 or f2-f1")
+	gradientSpec := flag.String("gradient", "#000000,#ffffff",
+		"comma separated colors, each optionally prefixed by its position in [0,1] as POS:COLOR")
+	palette := flag.String("palette", "", "gradient palette file (.ggr, .json or .png), overrides -gradient and -space")
+	space := flag.String("space", "srgb", "gradient color space: srgb, linear, hsv, hsl or oklab")
+	ditherName := flag.String("dither", "none", "dithering of the colored PNG: none, bayer, bluenoise or floyd-steinberg")
+	retro := flag.String("retro", "", "reduce the colored PNG to a retro palette: pico8 or gameboy")
+	out := flag.String("o", "", "colored PNG output file")
+	heightmap := flag.String("heightmap", "", "16-bit grayscale PNG output file")
+	raw := flag.String("raw", "", "raw little endian float32 output file")
+	flag.Parse()
+
+	if *out == "" && *heightmap == "" && *raw == "" {
+		fmt.Fprintln(os.Stderr, "No output file given, use -o, -heightmap or -raw")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	noiseType, ok := noiseTypes[*typeName]
+	if !ok {
+		exit("Unknown noise type:", *typeName)
+	}
+	w, h, err := parseSize(*size)
+	if err != nil {
+		exit("Invalid size:", err)
+	}
+	gradient, err := color.ParseGradient(*gradientSpec)
+	if err != nil {
+		exit("Invalid gradient:", err)
+	}
+	if gradient.Space, err = color.ParseSpace(*space); err != nil {
+		exit("Invalid color space:", err)
+	}
+	if *palette != "" {
+		if gradient, err = color.LoadGradient(*palette); err != nil {
+			exit("Could not load palette:", err)
+		}
+	}
+	method, err := dither.ParseMethod(*ditherName)
+	if err != nil {
+		exit("Invalid dithering:", err)
+	}
+	var retroPalette []color.RGB
+	if *retro != "" {
+		if retroPalette, ok = retroPalettes[*retro]; !ok {
+			exit("Unknown retro palette:", *retro)
+		}
+	}
+
+	p := noise.DefaultParams(noiseType, float32(*frequency), float32(*lacunarity),
+		float32(*gain), *octaves)
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "offset":
+			p.Offset = float32(*offset)
+		case "H":
+			p.H = float32(*hurst)
+		}
+	})
+	if *seed != 0 {
+		p.Generator = simplex.NewGenerator(*seed)
+	}
+	if *tile {
+		p.TileW, p.TileH = w, h
+	}
+	p.Worley.Seed = *seed
+	if p.Worley.Metric, ok = metrics[*metric]; !ok {
+		exit("Unknown metric:", *metric)
+	}
+	if p.Worley.Feature, ok = features[*feature]; !ok {
+		exit("Unknown feature:", *feature)
+	}
+
+	field := noise.MakeField(noiseType, w, h, p)
+	field.Normalize(0, 1)
+
+	if *out != "" {
+		var img image.Image = colorImage(field, gradient.Bake(256), method)
+		if retroPalette != nil {
+			img = dither.Palette(img, retroPalette, method)
+		}
+		if err := writePNG(*out, img); err != nil {
+			exit("Could not write image:", err)
+		}
+	}
+	if *heightmap != "" {
+		if err := writePNG(*heightmap, grayImage(field)); err != nil {
+			exit("Could not write heightmap:", err)
+		}
+	}
+	if *raw != "" {
+		if err := writeRaw(*raw, field); err != nil {
+			exit("Could not write raw file:", err)
+		}
+	}
+}
+
+func exit(msg string, v interface{}) {
+	fmt.Fprintln(os.Stderr, msg, v)
+	os.Exit(1)
+}
+
+func parseSize(s string) (w, h int, err error) {
+	parts := strings.Split(s, "x")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("%q is not WIDTHxHEIGHT", s)
+	}
+	if w, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, err
+	}
+	if h, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, err
+	}
+	if w <= 0 || h <= 0 {
+		return 0, 0, fmt.Errorf("%q is not a positive size", s)
+	}
+	return w, h, nil
+}
+
+func colorImage(f *noise.Field, gradient color.LUT, method dither.Method) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, f.W, f.H))
+	dither.Field(f.Values, f.W, gradient, method, img.Pix)
+	return img
+}
+
+func grayImage(f *noise.Field) *image.Gray16 {
+	img := image.NewGray16(image.Rect(0, 0, f.W, f.H))
+	for y := 0; y < f.H; y++ {
+		for x := 0; x < f.W; x++ {
+			img.SetGray16(x, y, imgcolor.Gray16{Y: uint16(f.At(x, y)*65535 + 0.5)})
+		}
+	}
+	return img
+}
+
+func writePNG(filename string, img image.Image) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeRaw(filename string, field *noise.Field) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	if err := binary.Write(w, binary.LittleEndian, field.Values); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
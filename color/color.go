@@ -0,0 +1,193 @@
+// Package color implements colors and multi-stop gradients shared by the
+// games and noise demos
+package color
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RGB is a 24 bit sRGB color. It implements image/color.Color as an
+// opaque color.
+type RGB struct {
+	R, G, B byte
+}
+
+// RGBA implements the image/color.Color interface
+func (c RGB) RGBA() (r, g, b, a uint32) {
+	return c.ToRGBA().RGBA()
+}
+
+// ToRGBA converts the color to an opaque image/color.RGBA
+func (c RGB) ToRGBA() color.RGBA {
+	return color.RGBA{c.R, c.G, c.B, 255}
+}
+
+// FromColor converts any image/color.Color to RGB, dropping its alpha
+func FromColor(c color.Color) RGB {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return RGB{n.R, n.G, n.B}
+}
+
+// ParseHex parses a #rrggbb color, the # is optional
+func ParseHex(s string) (RGB, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) != 6 {
+		return RGB{}, fmt.Errorf("%q is not a #rrggbb color", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return RGB{}, err
+	}
+	return RGB{byte(v >> 16), byte(v >> 8), byte(v)}, nil
+}
+
+// Hex returns the color as #rrggbb
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func lerp(b1, b2 byte, pct float32) byte {
+	return uint8(float32(b1) + pct*(float32(b2)-float32(b1)))
+}
+
+// Lerp is the linear interpolation between colors c1 and c2
+func Lerp(c1, c2 RGB, pct float32) RGB {
+	return RGB{
+		lerp(c1.R, c2.R, pct),
+		lerp(c1.G, c2.G, pct),
+		lerp(c1.B, c2.B, pct),
+	}
+}
+
+// Stop is a color at a position in [0,1] along a gradient
+type Stop struct {
+	Pos   float32
+	Color RGB
+}
+
+// Gradient blends between its stops, which are sorted by position, in
+// its color Space. Two stops at the same position make a hard edge.
+type Gradient struct {
+	Stops []Stop
+	Space Space
+}
+
+// NewGradient creates a Gradient from the given stops, in any order
+func NewGradient(stops ...Stop) Gradient {
+	sorted := make([]Stop, len(stops))
+	copy(sorted, stops)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Pos < sorted[j].Pos
+	})
+	return Gradient{Stops: sorted}
+}
+
+// Even creates a Gradient with the given colors spread evenly over [0,1]
+func Even(colors ...RGB) Gradient {
+	stops := make([]Stop, len(colors))
+	for i, c := range colors {
+		if len(colors) > 1 {
+			stops[i].Pos = float32(i) / float32(len(colors)-1)
+		}
+		stops[i].Color = c
+	}
+	return Gradient{Stops: stops}
+}
+
+// ParseGradient parses a comma separated list of [POS:]COLOR stops, such
+// as "0:#000080,0.4:#50a0f4,#ffffff". Stops without a position are spread
+// evenly, and positions must not decrease.
+func ParseGradient(spec string) (Gradient, error) {
+	parts := strings.Split(spec, ",")
+	g := Even(make([]RGB, len(parts))...)
+	for i, part := range parts {
+		colorSpec := part
+		if j := strings.Index(part, ":"); j >= 0 {
+			pos, err := strconv.ParseFloat(strings.TrimSpace(part[:j]), 32)
+			if err != nil {
+				return Gradient{}, err
+			}
+			g.Stops[i].Pos = float32(pos)
+			colorSpec = part[j+1:]
+		}
+
+		c, err := ParseHex(colorSpec)
+		if err != nil {
+			return Gradient{}, err
+		}
+		g.Stops[i].Color = c
+
+		if i > 0 && g.Stops[i].Pos < g.Stops[i-1].Pos {
+			return Gradient{}, fmt.Errorf("stop %q is before the previous one", part)
+		}
+	}
+	return g, nil
+}
+
+// String formats the gradient in the format read by ParseGradient
+func (g Gradient) String() string {
+	parts := make([]string, len(g.Stops))
+	for i, s := range g.Stops {
+		parts[i] = strconv.FormatFloat(float64(s.Pos), 'g', -1, 32) + ":" + s.Color.Hex()
+	}
+	return strings.Join(parts, ",")
+}
+
+// At returns the color of the gradient at pct. Positions before the first
+// stop or after the last one get the color of that stop.
+func (g Gradient) At(pct float32) RGB {
+	if len(g.Stops) == 0 {
+		return RGB{}
+	}
+	if pct <= g.Stops[0].Pos {
+		return g.Stops[0].Color
+	}
+
+	for i := 1; i < len(g.Stops); i++ {
+		if pct <= g.Stops[i].Pos {
+			a, b := g.Stops[i-1], g.Stops[i]
+			return Interpolate(a.Color, b.Color, (pct-a.Pos)/(b.Pos-a.Pos), g.Space)
+		}
+	}
+	return g.Stops[len(g.Stops)-1].Color
+}
+
+// Bake samples the gradient into a lookup table of n colors evenly spread
+// over [0,1], typically 256 for 8 bit or 65536 for 16 bit values. An n
+// below 1 gives an empty table.
+func (g Gradient) Bake(n int) LUT {
+	if n < 1 {
+		return LUT{}
+	}
+	result := make(LUT, n)
+	for i := range result {
+		pct := float32(0)
+		if n > 1 {
+			pct = float32(i) / float32(n-1)
+		}
+		result[i] = g.At(pct)
+	}
+	return result
+}
+
+// LUT is a gradient baked into a lookup table
+type LUT []RGB
+
+// At returns the color of the table at pct, clamped to [0,1]. An empty
+// table is black.
+func (l LUT) At(pct float32) RGB {
+	if len(l) == 0 {
+		return RGB{}
+	}
+	i := int(pct*float32(len(l)-1) + 0.5)
+	if i < 0 {
+		i = 0
+	} else if i > len(l)-1 {
+		i = len(l) - 1
+	}
+	return l[i]
+}
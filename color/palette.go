@@ -0,0 +1,253 @@
+package color
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoadGradient reads a gradient from a palette file. The format is picked
+// from the file extension: .ggr for GIMP gradients, .json for a stop list
+// and .png for a strip of colors, see ReadGGR, ReadJSON and ReadPNG.
+func LoadGradient(filename string) (Gradient, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Gradient{}, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ggr":
+		return ReadGGR(f)
+	case ".json":
+		return ReadJSON(f)
+	case ".png":
+		return ReadPNG(f)
+	default:
+		return Gradient{}, fmt.Errorf("unknown palette format %q", filepath.Ext(filename))
+	}
+}
+
+// SaveGradient writes the gradient to a palette file, in the format given
+// by the file extension like LoadGradient. PNG strips are 256 pixels wide.
+func SaveGradient(filename string, g Gradient) error {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".ggr" && ext != ".json" && ext != ".png" {
+		return fmt.Errorf("unknown palette format %q", filepath.Ext(filename))
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	switch ext {
+	case ".ggr":
+		name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		err = WriteGGR(f, g, name)
+	case ".json":
+		err = WriteJSON(f, g)
+	case ".png":
+		err = WritePNG(f, g, 256)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadGGR reads a GIMP gradient. Each segment becomes a stop at its left
+// end, its middle and its right end, which is exact for GIMP's linear RGB
+// blending. Other blending functions and HSV coloring are approximated
+// the same way, and alpha is dropped.
+func ReadGGR(r io.Reader) (Gradient, error) {
+	sc := bufio.NewScanner(r)
+	line := 0
+	next := func() (string, bool) {
+		for sc.Scan() {
+			line++
+			if text := strings.TrimSpace(sc.Text()); text != "" {
+				return text, true
+			}
+		}
+		return "", false
+	}
+
+	header, _ := next()
+	if header != "GIMP Gradient" {
+		return Gradient{}, fmt.Errorf("ggr: missing GIMP Gradient header")
+	}
+
+	text, ok := next()
+	if strings.HasPrefix(text, "Name:") {
+		text, ok = next()
+	}
+	if !ok {
+		return Gradient{}, fmt.Errorf("ggr: missing segment count")
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 1 {
+		return Gradient{}, fmt.Errorf("ggr: line %d: invalid segment count %q", line, text)
+	}
+
+	var g Gradient
+	for i := 0; i < n; i++ {
+		text, ok := next()
+		if !ok {
+			return Gradient{}, fmt.Errorf("ggr: expected %d segments, got %d", n, i)
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 11 {
+			return Gradient{}, fmt.Errorf("ggr: line %d: segment has %d fields, want at least 11", line, len(fields))
+		}
+		v := make([]float64, 11)
+		for j := range v {
+			if v[j], err = strconv.ParseFloat(fields[j], 64); err != nil {
+				return Gradient{}, fmt.Errorf("ggr: line %d: %v", line, err)
+			}
+		}
+
+		left, middle, right := float32(v[0]), float32(v[1]), float32(v[2])
+		lc := RGB{toByte(v[3]), toByte(v[4]), toByte(v[5])}
+		rc := RGB{toByte(v[7]), toByte(v[8]), toByte(v[9])}
+		g.Stops = append(g.Stops,
+			Stop{left, lc},
+			Stop{middle, Lerp(lc, rc, 0.5)},
+			Stop{right, rc},
+		)
+	}
+
+	return g, nil
+}
+
+// WriteGGR writes the gradient as a GIMP gradient with the given name,
+// one linear RGB segment between each pair of stops. Gradients in other
+// color spaces are written as if they were sRGB.
+func WriteGGR(w io.Writer, g Gradient, name string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "GIMP Gradient\nName: %s\n", name)
+
+	stops := g.Stops
+	if len(stops) == 0 {
+		stops = []Stop{{0, RGB{}}}
+	}
+	// GIMP segments cover [0,1], extend the end colors to it
+	if stops[0].Pos > 0 {
+		stops = append([]Stop{{0, stops[0].Color}}, stops...)
+	}
+	if last := stops[len(stops)-1]; last.Pos < 1 {
+		stops = append(stops, Stop{1, last.Color})
+	}
+
+	var segments []string
+	for i := 1; i < len(stops); i++ {
+		a, b := stops[i-1], stops[i]
+		if b.Pos <= a.Pos {
+			continue
+		}
+		segments = append(segments, fmt.Sprintf("%f %f %f %s 1.000000 %s 1.000000 0 0",
+			a.Pos, (a.Pos+b.Pos)/2, b.Pos, ggrColor(a.Color), ggrColor(b.Color)))
+	}
+	if len(segments) == 0 {
+		c := ggrColor(stops[0].Color)
+		segments = append(segments, fmt.Sprintf("0.000000 0.500000 1.000000 %s 1.000000 %s 1.000000 0 0", c, c))
+	}
+
+	fmt.Fprintln(bw, len(segments))
+	for _, s := range segments {
+		fmt.Fprintln(bw, s)
+	}
+	return bw.Flush()
+}
+
+func ggrColor(c RGB) string {
+	return fmt.Sprintf("%f %f %f", float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+}
+
+type jsonStop struct {
+	Pos   float32 `json:"pos"`
+	Color string  `json:"color"`
+}
+
+type jsonGradient struct {
+	Space string     `json:"space,omitempty"`
+	Stops []jsonStop `json:"stops"`
+}
+
+// ReadJSON reads a gradient stored as a JSON stop list, such as
+//
+//	{"space": "oklab", "stops": [{"pos": 0, "color": "#0000ff"}, {"pos": 1, "color": "#ffffff"}]}
+//
+// The space is optional and defaults to srgb.
+func ReadJSON(r io.Reader) (Gradient, error) {
+	var jg jsonGradient
+	if err := json.NewDecoder(r).Decode(&jg); err != nil {
+		return Gradient{}, err
+	}
+
+	stops := make([]Stop, len(jg.Stops))
+	for i, s := range jg.Stops {
+		c, err := ParseHex(s.Color)
+		if err != nil {
+			return Gradient{}, err
+		}
+		stops[i] = Stop{s.Pos, c}
+	}
+
+	g := NewGradient(stops...)
+	if jg.Space != "" {
+		space, err := ParseSpace(jg.Space)
+		if err != nil {
+			return Gradient{}, err
+		}
+		g.Space = space
+	}
+	return g, nil
+}
+
+// WriteJSON writes the gradient as a JSON stop list, see ReadJSON
+func WriteJSON(w io.Writer, g Gradient) error {
+	jg := jsonGradient{Space: g.Space.String(), Stops: make([]jsonStop, len(g.Stops))}
+	for i, s := range g.Stops {
+		jg.Stops[i] = jsonStop{s.Pos, s.Color.Hex()}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jg)
+}
+
+// ReadPNG reads a gradient from the first row of an image, one stop per
+// pixel spread evenly over [0,1]
+func ReadPNG(r io.Reader) (Gradient, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return Gradient{}, err
+	}
+
+	b := img.Bounds()
+	colors := make([]RGB, b.Dx())
+	for x := range colors {
+		colors[x] = FromColor(img.At(b.Min.X+x, b.Min.Y))
+	}
+	return Even(colors...), nil
+}
+
+// WritePNG writes the gradient baked into a 1 pixel high strip of the
+// given width
+func WritePNG(w io.Writer, g Gradient, width int) error {
+	img := image.NewRGBA(image.Rect(0, 0, width, 1))
+	for x, c := range g.Bake(width) {
+		img.SetRGBA(x, 0, c.ToRGBA())
+	}
+	return png.Encode(w, img)
+}
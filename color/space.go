@@ -0,0 +1,236 @@
+package color
+
+import (
+	"fmt"
+	"math"
+)
+
+// Space is the color space gradients interpolate in
+type Space uint
+
+const (
+	// SRGB interpolates the raw sRGB bytes
+	SRGB Space = iota
+	// LINEAR interpolates linear-light RGB, which keeps blends bright
+	LINEAR
+	// HSV interpolates hue, saturation and value along the shortest hue arc
+	HSV
+	// HSL interpolates hue, saturation and lightness along the shortest hue arc
+	HSL
+	// OKLAB interpolates in Björn Ottosson's perceptual Oklab space
+	OKLAB
+)
+
+var spaceNames = [...]string{"srgb", "linear", "hsv", "hsl", "oklab"}
+
+func (s Space) String() string {
+	if int(s) < len(spaceNames) {
+		return spaceNames[s]
+	}
+	return fmt.Sprintf("Space(%d)", uint(s))
+}
+
+// ParseSpace returns the Space with the given name, as returned by String
+func ParseSpace(name string) (Space, error) {
+	for i, n := range spaceNames {
+		if n == name {
+			return Space(i), nil
+		}
+	}
+	return SRGB, fmt.Errorf("unknown color space %q", name)
+}
+
+// Interpolate blends c1 and c2 by pct in the given color space
+func Interpolate(c1, c2 RGB, pct float32, space Space) RGB {
+	t := float64(pct)
+	switch space {
+	case LINEAR:
+		r1, g1, b1 := c1.linear()
+		r2, g2, b2 := c2.linear()
+		return fromLinear(lerp64(r1, r2, t), lerp64(g1, g2, t), lerp64(b1, b2, t))
+	case HSV:
+		h1, s1, v1 := c1.hsv()
+		h2, s2, v2 := c2.hsv()
+		h1, h2 = fixHues(h1, s1, h2, s2)
+		return fromHSV(lerpHue(h1, h2, t), lerp64(s1, s2, t), lerp64(v1, v2, t))
+	case HSL:
+		h1, s1, l1 := c1.hsl()
+		h2, s2, l2 := c2.hsl()
+		h1, h2 = fixHues(h1, s1, h2, s2)
+		return fromHSL(lerpHue(h1, h2, t), lerp64(s1, s2, t), lerp64(l1, l2, t))
+	case OKLAB:
+		L1, a1, b1 := c1.oklab()
+		L2, a2, b2 := c2.oklab()
+		return fromOklab(lerp64(L1, L2, t), lerp64(a1, a2, t), lerp64(b1, b2, t))
+	default:
+		return Lerp(c1, c2, pct)
+	}
+}
+
+func lerp64(a, b, t float64) float64 {
+	return a + t*(b-a)
+}
+
+// toByte converts a [0,1] channel to a byte, rounding and clamping it
+func toByte(v float64) byte {
+	v = math.Round(v * 255)
+	if v < 0 {
+		return 0
+	} else if v > 255 {
+		return 255
+	}
+	return byte(v)
+}
+
+func toLinear(c byte) float64 {
+	v := float64(c) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func fromLinearChannel(v float64) byte {
+	if v <= 0.0031308 {
+		return toByte(v * 12.92)
+	}
+	return toByte(1.055*math.Pow(v, 1/2.4) - 0.055)
+}
+
+func (c RGB) linear() (r, g, b float64) {
+	return toLinear(c.R), toLinear(c.G), toLinear(c.B)
+}
+
+func fromLinear(r, g, b float64) RGB {
+	return RGB{fromLinearChannel(r), fromLinearChannel(g), fromLinearChannel(b)}
+}
+
+// hueChroma returns the hue in degrees along with the largest, smallest
+// channel and their difference, the chroma
+func (c RGB) hueChroma() (h, max, min, chroma float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	max = math.Max(r, math.Max(g, b))
+	min = math.Min(r, math.Min(g, b))
+	chroma = max - min
+
+	switch {
+	case chroma == 0:
+		h = 0
+	case max == r:
+		h = math.Mod((g-b)/chroma, 6)
+	case max == g:
+		h = (b-r)/chroma + 2
+	default:
+		h = (r-g)/chroma + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, max, min, chroma
+}
+
+func (c RGB) hsv() (h, s, v float64) {
+	h, max, _, chroma := c.hueChroma()
+	if max > 0 {
+		s = chroma / max
+	}
+	return h, s, max
+}
+
+func (c RGB) hsl() (h, s, l float64) {
+	h, max, min, chroma := c.hueChroma()
+	l = (max + min) / 2
+	if l > 0 && l < 1 {
+		s = chroma / (1 - math.Abs(2*l-1))
+	}
+	return h, s, l
+}
+
+// fromHueChroma builds a color from its hue, chroma and the amount m
+// added to every channel
+func fromHueChroma(h, chroma, m float64) RGB {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	hp := h / 60
+	x := chroma * (1 - math.Abs(math.Mod(hp, 2)-1))
+
+	var r, g, b float64
+	switch {
+	case hp < 1:
+		r, g, b = chroma, x, 0
+	case hp < 2:
+		r, g, b = x, chroma, 0
+	case hp < 3:
+		r, g, b = 0, chroma, x
+	case hp < 4:
+		r, g, b = 0, x, chroma
+	case hp < 5:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return RGB{toByte(r + m), toByte(g + m), toByte(b + m)}
+}
+
+func fromHSV(h, s, v float64) RGB {
+	chroma := v * s
+	return fromHueChroma(h, chroma, v-chroma)
+}
+
+func fromHSL(h, s, l float64) RGB {
+	chroma := (1 - math.Abs(2*l-1)) * s
+	return fromHueChroma(h, chroma, l-chroma/2)
+}
+
+// fixHues gives a gray, whose hue is meaningless, the hue of the other
+// color so blending with it does not sweep through unrelated hues
+func fixHues(h1, s1, h2, s2 float64) (float64, float64) {
+	if s1 == 0 {
+		h1 = h2
+	}
+	if s2 == 0 {
+		h2 = h1
+	}
+	return h1, h2
+}
+
+// lerpHue interpolates two hues in degrees along the shortest arc
+func lerpHue(h1, h2, t float64) float64 {
+	d := h2 - h1
+	if d > 180 {
+		d -= 360
+	} else if d < -180 {
+		d += 360
+	}
+	return h1 + t*d
+}
+
+// oklab converts the color to Oklab, see https://bottosson.github.io/posts/oklab/
+func (c RGB) oklab() (L, a, b float64) {
+	r, g, bl := c.linear()
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+
+	L = 0.2104542553*l + 0.7936177850*m - 0.0040720468*s
+	a = 1.9779984951*l - 2.4285922050*m + 0.4505937099*s
+	b = 0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+	return L, a, b
+}
+
+func fromOklab(L, a, b float64) RGB {
+	l := L + 0.3963377774*a + 0.2158037573*b
+	m := L - 0.1055613458*a - 0.0638541728*b
+	s := L - 0.0894841775*a - 1.2914855480*b
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	return fromLinear(
+		+4.0767416621*l-3.3077115913*m+0.2309699292*s,
+		-1.2684380046*l+2.6097574011*m-0.3413193965*s,
+		-0.0041960863*l-0.7034186147*m+1.7076147010*s,
+	)
+}
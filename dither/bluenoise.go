@@ -0,0 +1,123 @@
+package dither
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+const blueNoiseSize = 64
+
+var (
+	blueNoiseOnce    sync.Once
+	blueNoiseTexture []float32
+)
+
+// blueNoise returns the tileable blue noise threshold texture, generated
+// the first time it is needed
+func blueNoise() []float32 {
+	blueNoiseOnce.Do(func() {
+		blueNoiseTexture = voidAndCluster(blueNoiseSize, 1.5, 1)
+	})
+	return blueNoiseTexture
+}
+
+// voidAndCluster generates a size x size blue noise threshold texture
+// with Ulichney's void-and-cluster method. Every pixel gets a distinct
+// rank, normalized to [0,1), such that the pixels below any threshold are
+// spread as evenly as possible.
+func voidAndCluster(size int, sigma float64, seed int64) []float32 {
+	n := size * size
+
+	// gaussian energy of a pixel seen from each offset, wrapping around
+	kernel := make([]float32, n)
+	for dy := 0; dy < size; dy++ {
+		for dx := 0; dx < size; dx++ {
+			x, y := float64(dx), float64(dy)
+			if dx > size/2 {
+				x = float64(size - dx)
+			}
+			if dy > size/2 {
+				y = float64(size - dy)
+			}
+			kernel[dy*size+dx] = float32(math.Exp(-(x*x + y*y) / (2 * sigma * sigma)))
+		}
+	}
+
+	on := make([]bool, n)
+	energy := make([]float32, n)
+	toggle := func(i int, set bool) {
+		on[i] = set
+		sign := float32(1)
+		if !set {
+			sign = -1
+		}
+		ix, iy := i%size, i/size
+		for y := 0; y < size; y++ {
+			row := ((y - iy + size) % size) * size
+			for x := 0; x < size; x++ {
+				energy[y*size+x] += sign * kernel[row+(x-ix+size)%size]
+			}
+		}
+	}
+	// tightest cluster is the set pixel with the most energy, largest
+	// void the unset pixel with the least
+	extreme := func(set bool) int {
+		best := -1
+		for i := range energy {
+			if on[i] != set {
+				continue
+			}
+			if best < 0 || (set && energy[i] > energy[best]) || (!set && energy[i] < energy[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+
+	// initial pattern: a tenth of the pixels at random, then moved from
+	// clusters to voids until it settles
+	r := rand.New(rand.NewSource(seed))
+	ones := n / 10
+	for _, i := range r.Perm(n)[:ones] {
+		toggle(i, true)
+	}
+	for {
+		cluster := extreme(true)
+		toggle(cluster, false)
+		void := extreme(false)
+		if void == cluster {
+			toggle(cluster, true)
+			break
+		}
+		toggle(void, true)
+	}
+
+	prototype := make([]bool, n)
+	copy(prototype, on)
+	protoEnergy := make([]float32, n)
+	copy(protoEnergy, energy)
+
+	rank := make([]int, n)
+	// phase 1: remove the tightest clusters of the prototype
+	for k := ones - 1; k >= 0; k-- {
+		i := extreme(true)
+		toggle(i, false)
+		rank[i] = k
+	}
+
+	// phase 2 and 3: fill the largest voids of the prototype
+	copy(on, prototype)
+	copy(energy, protoEnergy)
+	for k := ones; k < n; k++ {
+		i := extreme(false)
+		toggle(i, true)
+		rank[i] = k
+	}
+
+	result := make([]float32, n)
+	for i, k := range rank {
+		result[i] = (float32(k) + 0.5) / float32(n)
+	}
+	return result
+}
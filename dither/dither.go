@@ -0,0 +1,223 @@
+// Package dither hides the banding of quantized noise and reduces images
+// to small retro palettes
+package dither
+
+import (
+	"fmt"
+	"image"
+	imgcolor "image/color"
+	"math"
+
+	"github.com/dikaeinstein/games-with-go/color"
+)
+
+// Method is a dithering algorithm
+type Method uint
+
+const (
+	// NONE rounds to the closest color
+	NONE Method = iota
+	// BAYER is ordered dithering with an 8x8 Bayer matrix, which gives a
+	// regular crosshatch pattern
+	BAYER
+	// BLUENOISE is ordered dithering with a 64x64 blue noise texture, which
+	// gives an even pattern without visible structure
+	BLUENOISE
+	// FLOYDSTEINBERG diffuses the quantization error to the neighbouring
+	// pixels that have not been drawn yet
+	FLOYDSTEINBERG
+)
+
+var methodNames = [...]string{"none", "bayer", "bluenoise", "floyd-steinberg"}
+
+func (m Method) String() string {
+	if int(m) < len(methodNames) {
+		return methodNames[m]
+	}
+	return fmt.Sprintf("Method(%d)", uint(m))
+}
+
+// ParseMethod returns the Method with the given name, as returned by String
+func ParseMethod(name string) (Method, error) {
+	for i, n := range methodNames {
+		if n == name {
+			return Method(i), nil
+		}
+	}
+	return NONE, fmt.Errorf("unknown dithering method %q", name)
+}
+
+// threshold returns the ordered dithering threshold of x, y in [0,1), or
+// 0.5 for the methods that do not use one
+func threshold(method Method, x, y int) float32 {
+	switch method {
+	case BAYER:
+		return bayer[y&7][x&7]
+	case BLUENOISE:
+		n := blueNoise()
+		return n[(y&(blueNoiseSize-1))*blueNoiseSize+x&(blueNoiseSize-1)]
+	default:
+		return 0.5
+	}
+}
+
+// bayer is the 8x8 Bayer matrix normalized to [0,1)
+var bayer = func() (m [8][8]float32) {
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			// interleave the bits of x^y and y, reversed
+			v, xy := 0, x^y
+			for bit := 2; bit >= 0; bit-- {
+				v = v<<2 | (xy>>uint(2-bit)&1)<<1 | y>>uint(2-bit)&1
+			}
+			m[y][x] = (float32(v) + 0.5) / 64
+		}
+	}
+	return m
+}()
+
+// Field draws a w wide field of values in [0,1] through the lookup table
+// into the RGBA pixels buffer. Dithering picks between the two table
+// entries around each value, so smooth gradients do not show bands.
+func Field(values []float32, w int, lut color.LUT, method Method, pixels []byte) {
+	last := float32(len(lut) - 1)
+
+	// Floyd-Steinberg error of the current and the next row
+	var cur, next []float32
+	if method == FLOYDSTEINBERG {
+		cur = make([]float32, w+2)
+		next = make([]float32, w+2)
+	}
+
+	for i, v := range values {
+		x, y := i%w, i/w
+		if method == FLOYDSTEINBERG && x == 0 && y > 0 {
+			cur, next = next, cur
+			for j := range next {
+				next[j] = 0
+			}
+		}
+
+		pos := v * last
+		var index int
+		if method == FLOYDSTEINBERG {
+			pos += cur[x+1]
+			index = int(math.Floor(float64(pos) + 0.5))
+			err := pos - float32(index)
+			cur[x+2] += err * 7 / 16
+			next[x] += err * 3 / 16
+			next[x+1] += err * 5 / 16
+			next[x+2] += err * 1 / 16
+		} else {
+			index = int(math.Floor(float64(pos + threshold(method, x, y))))
+		}
+
+		if index < 0 {
+			index = 0
+		} else if index > len(lut)-1 {
+			index = len(lut) - 1
+		}
+
+		c := lut[index]
+		p := i * 4
+		pixels[p] = c.R
+		pixels[p+1] = c.G
+		pixels[p+2] = c.B
+		pixels[p+3] = 255
+	}
+}
+
+// Palette reduces img to the colors of the palette, using the given
+// dithering method to spread the difference between the image and the
+// closest palette color
+func Palette(img image.Image, palette []color.RGB, method Method) *image.Paletted {
+	b := img.Bounds()
+	pal := make(imgcolor.Palette, len(palette))
+	for i, c := range palette {
+		pal[i] = c.ToRGBA()
+	}
+	result := image.NewPaletted(b, pal)
+
+	// ordered dithering moves each channel by up to half the typical
+	// distance between palette colors
+	spread := float32(255 / math.Cbrt(float64(len(palette))))
+
+	w := b.Dx()
+	var cur, next [][3]float32
+	if method == FLOYDSTEINBERG {
+		cur = make([][3]float32, w+2)
+		next = make([][3]float32, w+2)
+	}
+
+	for y := 0; y < b.Dy(); y++ {
+		if method == FLOYDSTEINBERG {
+			cur, next = next, cur
+			for j := range next {
+				next[j] = [3]float32{}
+			}
+		}
+
+		for x := 0; x < w; x++ {
+			c := color.FromColor(img.At(b.Min.X+x, b.Min.Y+y))
+			want := [3]float32{float32(c.R), float32(c.G), float32(c.B)}
+
+			switch method {
+			case FLOYDSTEINBERG:
+				for k := range want {
+					want[k] += cur[x+1][k]
+				}
+			case BAYER, BLUENOISE:
+				d := (threshold(method, x, y) - 0.5) * spread
+				for k := range want {
+					want[k] += d
+				}
+			}
+
+			index := closest(palette, want)
+			result.SetColorIndex(b.Min.X+x, b.Min.Y+y, uint8(index))
+
+			if method == FLOYDSTEINBERG {
+				got := palette[index]
+				gotf := [3]float32{float32(got.R), float32(got.G), float32(got.B)}
+				for k := range want {
+					err := want[k] - gotf[k]
+					cur[x+2][k] += err * 7 / 16
+					next[x][k] += err * 3 / 16
+					next[x+1][k] += err * 5 / 16
+					next[x+2][k] += err * 1 / 16
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// closest returns the index of the palette color closest to c
+func closest(palette []color.RGB, c [3]float32) int {
+	best, bestDist := 0, float32(math.MaxFloat32)
+	for i, p := range palette {
+		dr := c[0] - float32(p.R)
+		dg := c[1] - float32(p.G)
+		db := c[2] - float32(p.B)
+		// weight the channels by how sensitive the eye is to them
+		d := 0.3*dr*dr + 0.59*dg*dg + 0.11*db*db
+		if d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// PICO8 is the 16 color palette of the PICO-8 fantasy console
+var PICO8 = []color.RGB{
+	{R: 0x00, G: 0x00, B: 0x00}, {R: 0x1d, G: 0x2b, B: 0x53}, {R: 0x7e, G: 0x25, B: 0x53}, {R: 0x00, G: 0x87, B: 0x51},
+	{R: 0xab, G: 0x52, B: 0x36}, {R: 0x5f, G: 0x57, B: 0x4f}, {R: 0xc2, G: 0xc3, B: 0xc7}, {R: 0xff, G: 0xf1, B: 0xe8},
+	{R: 0xff, G: 0x00, B: 0x4d}, {R: 0xff, G: 0xa3, B: 0x00}, {R: 0xff, G: 0xec, B: 0x27}, {R: 0x00, G: 0xe4, B: 0x36},
+	{R: 0x29, G: 0xad, B: 0xff}, {R: 0x83, G: 0x76, B: 0x9c}, {R: 0xff, G: 0x77, B: 0xa8}, {R: 0xff, G: 0xcc, B: 0xaa},
+}
+
+// GAMEBOY is the 4 shade green palette of the original Game Boy
+var GAMEBOY = []color.RGB{
+	{R: 0x0f, G: 0x38, B: 0x0f}, {R: 0x30, G: 0x62, B: 0x30}, {R: 0x8b, G: 0xac, B: 0x0f}, {R: 0x9b, G: 0xbc, B: 0x0f},
+}
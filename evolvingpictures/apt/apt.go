@@ -1,6 +1,11 @@
 package apt
 
-import "math"
+import (
+	"math"
+	"strconv"
+
+	"github.com/dikaeinstein/games-with-go/noise"
+)
 
 // Node describes a node of the Abstract Picture Tree(APT)
 type Node interface {
@@ -22,6 +27,62 @@ type Double struct {
 	RightChild Node
 }
 
+// Triple is a node with three children nodes
+type Triple struct {
+	LeftChild   Node
+	MiddleChild Node
+	RightChild  Node
+}
+
+// Branch is a node with children nodes, the operands of its operator
+type Branch interface {
+	Node
+	Children() []Node
+	SetChild(i int, child Node)
+}
+
+// Children returns the child node
+func (s *Single) Children() []Node {
+	return []Node{s.Child}
+}
+
+// SetChild replaces the child node, i must be 0
+func (s *Single) SetChild(i int, child Node) {
+	s.Child = child
+}
+
+// Children returns the left and right children
+func (d *Double) Children() []Node {
+	return []Node{d.LeftChild, d.RightChild}
+}
+
+// SetChild replaces the left child when i is 0 and the right one when i
+// is 1
+func (d *Double) SetChild(i int, child Node) {
+	if i == 0 {
+		d.LeftChild = child
+	} else {
+		d.RightChild = child
+	}
+}
+
+// Children returns the left, middle and right children
+func (t *Triple) Children() []Node {
+	return []Node{t.LeftChild, t.MiddleChild, t.RightChild}
+}
+
+// SetChild replaces the left, middle or right child when i is 0, 1 or 2
+func (t *Triple) SetChild(i int, child Node) {
+	switch i {
+	case 0:
+		t.LeftChild = child
+	case 1:
+		t.MiddleChild = child
+	default:
+		t.RightChild = child
+	}
+}
+
 // OpPlus is the plus operator node
 type OpPlus struct {
 	Double
@@ -73,3 +134,264 @@ func (ops *OpSin) Eval(x, y float32) float32 {
 func (ops *OpSin) String() string {
 	return "( Sin " + ops.Child.String() + " )"
 }
+
+// OpMinus is the minus operator node
+type OpMinus struct {
+	Double
+}
+
+// Eval evaluates the difference of the operands
+func (op *OpMinus) Eval(x, y float32) float32 {
+	return op.LeftChild.Eval(x, y) - op.RightChild.Eval(x, y)
+}
+
+func (op *OpMinus) String() string {
+	return "( - " + op.LeftChild.String() + " " + op.RightChild.String() + " )"
+}
+
+// OpMult is the multiply operator node
+type OpMult struct {
+	Double
+}
+
+// Eval evaluates the product of the operands
+func (op *OpMult) Eval(x, y float32) float32 {
+	return op.LeftChild.Eval(x, y) * op.RightChild.Eval(x, y)
+}
+
+func (op *OpMult) String() string {
+	return "( * " + op.LeftChild.String() + " " + op.RightChild.String() + " )"
+}
+
+// OpDiv is the protected divide operator node
+type OpDiv struct {
+	Double
+}
+
+// Eval evaluates the quotient of the operands. Dividing by zero gives 0
+// instead of infinity so one node cannot blank the whole picture.
+func (op *OpDiv) Eval(x, y float32) float32 {
+	r := op.RightChild.Eval(x, y)
+	if r == 0 {
+		return 0
+	}
+	return op.LeftChild.Eval(x, y) / r
+}
+
+func (op *OpDiv) String() string {
+	return "( / " + op.LeftChild.String() + " " + op.RightChild.String() + " )"
+}
+
+// OpAtan2 is the arc tangent of the quotient node
+type OpAtan2 struct {
+	Double
+}
+
+// Eval evaluates atan2(left, right)
+func (op *OpAtan2) Eval(x, y float32) float32 {
+	return float32(math.Atan2(float64(op.LeftChild.Eval(x, y)), float64(op.RightChild.Eval(x, y))))
+}
+
+func (op *OpAtan2) String() string {
+	return "( Atan2 " + op.LeftChild.String() + " " + op.RightChild.String() + " )"
+}
+
+// OpCos is the Cosine operation node
+type OpCos struct {
+	Single
+}
+
+// Eval evaluates the cos(x)
+func (op *OpCos) Eval(x, y float32) float32 {
+	return float32(math.Cos(float64(op.Child.Eval(x, y))))
+}
+
+func (op *OpCos) String() string {
+	return "( Cos " + op.Child.String() + " )"
+}
+
+// OpAbs is the absolute value operation node
+type OpAbs struct {
+	Single
+}
+
+// Eval evaluates the |x|
+func (op *OpAbs) Eval(x, y float32) float32 {
+	return float32(math.Abs(float64(op.Child.Eval(x, y))))
+}
+
+func (op *OpAbs) String() string {
+	return "( Abs " + op.Child.String() + " )"
+}
+
+// OpFloor is the floor operation node
+type OpFloor struct {
+	Single
+}
+
+// Eval evaluates the floor(x)
+func (op *OpFloor) Eval(x, y float32) float32 {
+	return float32(math.Floor(float64(op.Child.Eval(x, y))))
+}
+
+func (op *OpFloor) String() string {
+	return "( Floor " + op.Child.String() + " )"
+}
+
+// OpLog is the protected natural logarithm operation node
+type OpLog struct {
+	Single
+}
+
+// Eval evaluates the log(|x|), which is 0 for 0
+func (op *OpLog) Eval(x, y float32) float32 {
+	v := math.Abs(float64(op.Child.Eval(x, y)))
+	if v == 0 {
+		return 0
+	}
+	return float32(math.Log(v))
+}
+
+func (op *OpLog) String() string {
+	return "( Log " + op.Child.String() + " )"
+}
+
+// OpSqrt is the protected square root operation node
+type OpSqrt struct {
+	Single
+}
+
+// Eval evaluates the sqrt(|x|)
+func (op *OpSqrt) Eval(x, y float32) float32 {
+	return float32(math.Sqrt(math.Abs(float64(op.Child.Eval(x, y)))))
+}
+
+func (op *OpSqrt) String() string {
+	return "( Sqrt " + op.Child.String() + " )"
+}
+
+// OpExp is the exponential operation node
+type OpExp struct {
+	Single
+}
+
+// Eval evaluates the e^x
+func (op *OpExp) Eval(x, y float32) float32 {
+	return float32(math.Exp(float64(op.Child.Eval(x, y))))
+}
+
+func (op *OpExp) String() string {
+	return "( Exp " + op.Child.String() + " )"
+}
+
+// OpLerp is the linear interpolation node
+type OpLerp struct {
+	Triple
+}
+
+// Eval evaluates the interpolation from the left to the middle operand.
+// The right operand is the percent, mapped from [-1,1] to [0,1].
+func (op *OpLerp) Eval(x, y float32) float32 {
+	a := op.LeftChild.Eval(x, y)
+	b := op.MiddleChild.Eval(x, y)
+	pct := (op.RightChild.Eval(x, y) + 1) / 2
+	return a + pct*(b-a)
+}
+
+func (op *OpLerp) String() string {
+	return "( Lerp " + op.LeftChild.String() + " " + op.MiddleChild.String() + " " +
+		op.RightChild.String() + " )"
+}
+
+// OpClip is the clip operation node
+type OpClip struct {
+	Double
+}
+
+// Eval evaluates the left operand clipped to [-|right|, |right|]
+func (op *OpClip) Eval(x, y float32) float32 {
+	v := op.LeftChild.Eval(x, y)
+	limit := float32(math.Abs(float64(op.RightChild.Eval(x, y))))
+	if v > limit {
+		return limit
+	} else if v < -limit {
+		return -limit
+	}
+	return v
+}
+
+func (op *OpClip) String() string {
+	return "( Clip " + op.LeftChild.String() + " " + op.RightChild.String() + " )"
+}
+
+// OpWrap is the wrap operation node
+type OpWrap struct {
+	Single
+}
+
+// Eval evaluates x wrapped around into [-1,1)
+func (op *OpWrap) Eval(x, y float32) float32 {
+	v := op.Child.Eval(x, y)
+	return v - 2*float32(math.Floor(float64(v+1)/2))
+}
+
+func (op *OpWrap) String() string {
+	return "( Wrap " + op.Child.String() + " )"
+}
+
+// OpConstant is the constant value node
+type OpConstant struct {
+	Value float32
+}
+
+// Eval evaluates the constant value
+func (op OpConstant) Eval(x, y float32) float32 {
+	return op.Value
+}
+
+func (op OpConstant) String() string {
+	return strconv.FormatFloat(float64(op.Value), 'g', -1, 32)
+}
+
+// The noise nodes sample fractal noise at the point given by their
+// operands with fixed parameters. noiseScale brings the values from the
+// range of the noise package, which follows simplex.SNoise2, back to
+// about [-1,1].
+const (
+	noiseScale      = 40
+	noiseFrequency  = 2
+	noiseLacunarity = 2
+	noiseGain       = 0.5
+	noiseOctaves    = 3
+)
+
+// OpFbm is the fractal brownian motion noise node
+type OpFbm struct {
+	Double
+}
+
+// Eval evaluates the fbm noise at (left, right)
+func (op *OpFbm) Eval(x, y float32) float32 {
+	return noiseScale * noise.Fbm2(op.LeftChild.Eval(x, y), op.RightChild.Eval(x, y),
+		noiseFrequency, noiseLacunarity, noiseGain, noiseOctaves)
+}
+
+func (op *OpFbm) String() string {
+	return "( FBM " + op.LeftChild.String() + " " + op.RightChild.String() + " )"
+}
+
+// OpTurbulence is the turbulence noise node
+type OpTurbulence struct {
+	Double
+}
+
+// Eval evaluates the turbulence noise at (left, right), moved down so it
+// is centered like the other nodes
+func (op *OpTurbulence) Eval(x, y float32) float32 {
+	return 2*noiseScale*noise.Turbulence(op.LeftChild.Eval(x, y), op.RightChild.Eval(x, y),
+		noiseFrequency, noiseLacunarity, noiseGain, noiseOctaves) - 1
+}
+
+func (op *OpTurbulence) String() string {
+	return "( Turbulence " + op.LeftChild.String() + " " + op.RightChild.String() + " )"
+}
@@ -0,0 +1,196 @@
+package apt
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// operatorsByType finds the operator of a node, to make new nodes of the
+// same type
+var operatorsByType = func() map[reflect.Type]operator {
+	m := make(map[reflect.Type]operator, len(operators))
+	for _, op := range operators {
+		m[reflect.TypeOf(op.new())] = op
+	}
+	return m
+}()
+
+// Copy returns a deep copy of the tree n, so it can be changed without
+// changing n. It panics if n holds a Branch type that is not one of the
+// package operators, since it cannot make a new node of that type.
+func Copy(n Node) Node {
+	b, ok := n.(Branch)
+	if !ok {
+		// leaves are values and never change
+		return n
+	}
+
+	op, ok := operatorsByType[reflect.TypeOf(n)]
+	if !ok {
+		panic(fmt.Sprintf("apt: cannot copy %T, it is not a registered operator", n))
+	}
+	c := op.new().(Branch)
+	for i, child := range b.Children() {
+		c.SetChild(i, Copy(child))
+	}
+	return c
+}
+
+// Size returns the number of nodes of the tree n, leaves included
+func Size(n Node) int {
+	size := 1
+	if b, ok := n.(Branch); ok {
+		for _, child := range b.Children() {
+			size += Size(child)
+		}
+	}
+	return size
+}
+
+// Depth returns the number of operators on the longest path from the root
+// of n to a leaf, as limited by Generator.Tree
+func Depth(n Node) int {
+	b, ok := n.(Branch)
+	if !ok {
+		return 0
+	}
+	depth := 0
+	for _, child := range b.Children() {
+		if d := Depth(child); d > depth {
+			depth = d
+		}
+	}
+	return depth + 1
+}
+
+// place is the location of a node in a tree: the child index of its
+// parent, or a nil parent for the root
+type place struct {
+	parent Branch
+	index  int
+	node   Node
+	depth  int
+}
+
+// places lists every node of the tree n in depth first order
+func places(n Node) []place {
+	var list []place
+	var walk func(parent Branch, index int, n Node, depth int)
+	walk = func(parent Branch, index int, n Node, depth int) {
+		list = append(list, place{parent, index, n, depth})
+		if b, ok := n.(Branch); ok {
+			for i, child := range b.Children() {
+				walk(b, i, child, depth+1)
+			}
+		}
+	}
+	walk(nil, 0, n, 0)
+	return list
+}
+
+// replace puts n at p in the tree root and returns the new root
+func replace(root Node, p place, n Node) Node {
+	if p.parent == nil {
+		return n
+	}
+	p.parent.SetChild(p.index, n)
+	return root
+}
+
+// randomPlace returns a random node location of the tree n
+func (g *Generator) randomPlace(n Node) place {
+	list := places(n)
+	return list[g.rand.Intn(len(list))]
+}
+
+// Mutate returns a copy of n with one random node replaced by a random
+// node of the same arity, keeping its children. Constants are nudged
+// rather than replaced half of the time.
+func (g *Generator) Mutate(n Node) Node {
+	n = Copy(n)
+	p := g.randomPlace(n)
+
+	b, ok := p.node.(Branch)
+	if !ok {
+		if c, ok := p.node.(OpConstant); ok && g.rand.Intn(2) == 0 {
+			return replace(n, p, OpConstant{c.Value + float32(g.rand.NormFloat64()*0.1)})
+		}
+		return replace(n, p, g.Leaf())
+	}
+
+	children := b.Children()
+	op, ok := g.pick(func(arity int) bool { return arity == len(children) })
+	if !ok {
+		return n
+	}
+	m := op.new().(Branch)
+	for i, child := range children {
+		m.SetChild(i, child)
+	}
+	return replace(n, p, m)
+}
+
+// ReplaceSubtree returns a copy of n with one random subtree replaced by
+// a new random tree, see Tree
+func (g *Generator) ReplaceSubtree(n Node, size, maxDepth int) Node {
+	n = Copy(n)
+	p := g.randomPlace(n)
+	return replace(n, p, g.Tree(size, maxDepth))
+}
+
+// Crossover returns two children of the parents a and b, made by swapping
+// a random subtree of a with a random subtree of b. The parents are not
+// changed.
+func (g *Generator) Crossover(a, b Node) (Node, Node) {
+	a, b = Copy(a), Copy(b)
+	pa, pb := g.randomPlace(a), g.randomPlace(b)
+	a = replace(a, pa, pb.node)
+	b = replace(b, pb, pa.node)
+	return a, b
+}
+
+// Prune returns a copy of n where the operators deeper than maxDepth, as
+// counted by Depth, are replaced by random leaves
+func (g *Generator) Prune(n Node, maxDepth int) Node {
+	var prune func(n Node, depth int) Node
+	prune = func(n Node, depth int) Node {
+		b, ok := n.(Branch)
+		if !ok {
+			return n
+		}
+		if depth >= maxDepth {
+			return g.Leaf()
+		}
+		for i, child := range b.Children() {
+			b.SetChild(i, prune(child, depth+1))
+		}
+		return b
+	}
+	return prune(Copy(n), 0)
+}
+
+// Breed returns n children of the parents. Each child is the crossover of
+// two random parents, or a copy of the only one, then usually mutated and
+// finally pruned to maxDepth.
+func (g *Generator) Breed(parents []Node, n, maxDepth int) []Node {
+	if len(parents) == 0 {
+		return nil
+	}
+
+	children := make([]Node, 0, n)
+	for len(children) < n {
+		child := Copy(parents[g.rand.Intn(len(parents))])
+		if len(parents) > 1 {
+			child, _ = g.Crossover(child, parents[g.rand.Intn(len(parents))])
+		}
+
+		switch r := g.rand.Float64(); {
+		case r < 0.5:
+			child = g.Mutate(child)
+		case r < 0.75:
+			child = g.ReplaceSubtree(child, 3, 3)
+		}
+		children = append(children, g.Prune(child, maxDepth))
+	}
+	return children
+}
@@ -0,0 +1,174 @@
+package apt
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+// SyntaxError describes where and why Parse failed. Line and Column start
+// at 1.
+type SyntaxError struct {
+	Line, Column int
+	Msg          string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// token is a parenthesis or a symbol with its position. An empty text is
+// the end of the input.
+type token struct {
+	text         string
+	line, column int
+}
+
+// tokenize splits s on white space and parentheses
+func tokenize(s string) []token {
+	var tokens []token
+	line, column := 1, 1
+	start := -1
+	var startLine, startColumn int
+	runes := []rune(s)
+
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{string(runes[start:end]), startLine, startColumn})
+			start = -1
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '(' || r == ')':
+			flush(i)
+			tokens = append(tokens, token{string(r), line, column})
+		case unicode.IsSpace(r):
+			flush(i)
+		case start < 0:
+			start, startLine, startColumn = i, line, column
+		}
+
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	flush(len(runes))
+	return append(tokens, token{"", line, column})
+}
+
+// operatorsByName finds the operator of a symbol
+var operatorsByName = func() map[string]operator {
+	m := make(map[string]operator, len(operators))
+	for _, op := range operators {
+		m[op.name] = op
+	}
+	return m
+}()
+
+// Parse reads back a tree written by its String method, such as
+// ( + ( Sin X ) 0.5 ). Symbols are separated by white space, which may
+// include new lines.
+func Parse(s string) (Node, error) {
+	p := parser{tokens: tokenize(s)}
+	n, err := p.node()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.next(); t.text != "" {
+		return nil, p.errorf(t, "unexpected %q after the tree", t.text)
+	}
+	return n, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{t.line, t.column, fmt.Sprintf(format, args...)}
+}
+
+// node parses a leaf or an operator with its operands in parentheses
+func (p *parser) node() (Node, error) {
+	t := p.next()
+	switch t.text {
+	case "":
+		return nil, p.errorf(t, "unexpected end of input")
+	case ")":
+		return nil, p.errorf(t, "unexpected )")
+	case "(":
+		return p.operator()
+	case "X":
+		return OpX{}, nil
+	case "Y":
+		return OpY{}, nil
+	}
+
+	v, err := strconv.ParseFloat(t.text, 32)
+	if err != nil {
+		if op, ok := operatorsByName[t.text]; ok {
+			if op.arity == 0 {
+				return nil, p.leafError(t)
+			}
+			return nil, p.errorf(t, "operator %s must be in parentheses", t.text)
+		}
+		return nil, p.errorf(t, "unknown symbol %q", t.text)
+	}
+	return OpConstant{float32(v)}, nil
+}
+
+// operator parses an operator and its operands, after the opening
+// parenthesis
+func (p *parser) operator() (Node, error) {
+	t := p.next()
+	op, ok := operatorsByName[t.text]
+	if !ok {
+		if t.text == "" {
+			return nil, p.errorf(t, "unexpected end of input")
+		}
+		return nil, p.errorf(t, "unknown operator %q", t.text)
+	}
+	if op.arity == 0 {
+		return nil, p.leafError(t)
+	}
+
+	n := op.new().(Branch)
+	for i := 0; i < op.arity; i++ {
+		child, err := p.node()
+		if err != nil {
+			return nil, err
+		}
+		n.SetChild(i, child)
+	}
+
+	if end := p.next(); end.text != ")" {
+		if end.text == "" {
+			return nil, p.errorf(end, "missing ) to close %s", op.name)
+		}
+		return nil, p.errorf(end, "%s takes %d operands, expected ) but got %q", op.name, op.arity, end.text)
+	}
+	return n, nil
+}
+
+// leafError reports a leaf written by its operator name: Constant, which
+// is written as its value, or X or Y in parentheses
+func (p *parser) leafError(t token) error {
+	if t.text == "Constant" {
+		return p.errorf(t, "Constant needs a value, write the number itself")
+	}
+	return p.errorf(t, "%s takes no operands, write it without parentheses", t.text)
+}
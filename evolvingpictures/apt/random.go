@@ -0,0 +1,173 @@
+package apt
+
+import "math/rand"
+
+// operator describes a node type that trees can be built from. name is
+// the symbol the node String uses and arity its number of children.
+type operator struct {
+	name  string
+	arity int
+	new   func() Node
+}
+
+// operators lists every node type, leaves first. Random choices walk it in
+// this order so a seed always builds the same tree.
+var operators = []operator{
+	{"X", 0, func() Node { return OpX{} }},
+	{"Y", 0, func() Node { return OpY{} }},
+	{"Constant", 0, func() Node { return OpConstant{} }},
+	{"+", 2, func() Node { return &OpPlus{} }},
+	{"-", 2, func() Node { return &OpMinus{} }},
+	{"*", 2, func() Node { return &OpMult{} }},
+	{"/", 2, func() Node { return &OpDiv{} }},
+	{"Atan2", 2, func() Node { return &OpAtan2{} }},
+	{"Sin", 1, func() Node { return &OpSin{} }},
+	{"Cos", 1, func() Node { return &OpCos{} }},
+	{"Abs", 1, func() Node { return &OpAbs{} }},
+	{"Floor", 1, func() Node { return &OpFloor{} }},
+	{"Log", 1, func() Node { return &OpLog{} }},
+	{"Sqrt", 1, func() Node { return &OpSqrt{} }},
+	{"Exp", 1, func() Node { return &OpExp{} }},
+	{"Lerp", 3, func() Node { return &OpLerp{} }},
+	{"Clip", 2, func() Node { return &OpClip{} }},
+	{"Wrap", 1, func() Node { return &OpWrap{} }},
+	{"FBM", 2, func() Node { return &OpFbm{} }},
+	{"Turbulence", 2, func() Node { return &OpTurbulence{} }},
+}
+
+// DefaultWeights returns the default relative chance of picking each node
+// type, keyed by the name its String uses, "Constant" for OpConstant
+func DefaultWeights() map[string]float64 {
+	w := make(map[string]float64, len(operators))
+	for _, op := range operators {
+		w[op.name] = 1
+	}
+	// keep the pictures from going flat or blowing up too often
+	w["Floor"] = 0.5
+	w["Exp"] = 0.5
+	w["FBM"] = 0.5
+	w["Turbulence"] = 0.5
+	return w
+}
+
+// Generator builds random trees. All its randomness comes from one seeded
+// source, so the same seed and calls give the same trees.
+type Generator struct {
+	rand *rand.Rand
+	// Weights is the relative chance of picking each node type, keyed by
+	// the name its String uses. Missing, zero or negative weights are never
+	// picked.
+	Weights map[string]float64
+}
+
+// NewGenerator creates a Generator seeded with seed, using the
+// DefaultWeights
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rand.New(rand.NewSource(seed)), DefaultWeights()}
+}
+
+// pick returns a random operator whose arity satisfies accept, by weight.
+// It returns false if none has a positive weight.
+func (g *Generator) pick(accept func(arity int) bool) (operator, bool) {
+	var total float64
+	for _, op := range operators {
+		if w := g.Weights[op.name]; w > 0 && accept(op.arity) {
+			total += w
+		}
+	}
+	if total == 0 {
+		return operator{}, false
+	}
+
+	r := g.rand.Float64() * total
+	var last operator
+	for _, op := range operators {
+		if !accept(op.arity) || g.Weights[op.name] <= 0 {
+			continue
+		}
+		last = op
+		r -= g.Weights[op.name]
+		if r < 0 {
+			break
+		}
+	}
+	return last, true
+}
+
+// Leaf returns a random leaf node. Constants are in [-1,1]. When no leaf
+// has a weight, X is returned.
+func (g *Generator) Leaf() Node {
+	op, ok := g.pick(func(arity int) bool { return arity == 0 })
+	if !ok {
+		return OpX{}
+	}
+	if op.name == "Constant" {
+		return OpConstant{g.rand.Float32()*2 - 1}
+	}
+	return op.new()
+}
+
+// Operator returns a random operator node with nil children, or false
+// when no operator has a weight
+func (g *Generator) Operator() (Branch, bool) {
+	op, ok := g.pick(func(arity int) bool { return arity > 0 })
+	if !ok {
+		return nil, false
+	}
+	return op.new().(Branch), true
+}
+
+// slot is a missing child of a node in a tree being built
+type slot struct {
+	parent Branch
+	index  int
+	depth  int
+}
+
+// Tree returns a random tree of up to size operator nodes, no deeper than
+// maxDepth operators. Operators are added at random places until the size
+// or the depth is reached, then every remaining operand gets a random
+// leaf, so the tree is always complete.
+func (g *Generator) Tree(size, maxDepth int) Node {
+	if size <= 0 || maxDepth <= 0 {
+		return g.Leaf()
+	}
+	root, ok := g.Operator()
+	if !ok {
+		return g.Leaf()
+	}
+
+	slots := addSlots(nil, root, 1)
+	for count := 1; count < size; count++ {
+		// only the slots above the depth limit can take an operator
+		var open []int
+		for i, s := range slots {
+			if s.depth < maxDepth {
+				open = append(open, i)
+			}
+		}
+		if len(open) == 0 {
+			break
+		}
+
+		i := open[g.rand.Intn(len(open))]
+		s := slots[i]
+		op, _ := g.Operator()
+		s.parent.SetChild(s.index, op)
+		slots[i] = slots[len(slots)-1]
+		slots = addSlots(slots[:len(slots)-1], op, s.depth+1)
+	}
+
+	for _, s := range slots {
+		s.parent.SetChild(s.index, g.Leaf())
+	}
+	return root
+}
+
+// addSlots appends the operand slots of the operator n at depth to slots
+func addSlots(slots []slot, n Branch, depth int) []slot {
+	for i := range n.Children() {
+		slots = append(slots, slot{n, i, depth})
+	}
+	return slots
+}
@@ -1,30 +1,101 @@
+// Command evolvingpictures breeds pictures drawn by random expression
+// trees. It shows a grid of candidates: click your favorites, then press
+// space to breed the next generation from them.
+//
+// Keys:
+//
+//	space, return    breed the next generation from the favorites
+//	u, backspace     undo the last generation
+//	r                start over with random pictures
+//	s                save the tree and a high resolution PNG of the
+//	                 picture under the mouse
+//	escape           quit
 package main
 
 import (
+	"flag"
 	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
-	"github.com/dikaeinstein/games-with-go/balloons2/balloon"
 	"github.com/dikaeinstein/games-with-go/evolvingpictures/apt"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-type rgba struct {
-	r, g, b byte
-}
-
 const winWidth = 800
 const winHeight = 600
-const winDepth = 100
+
+// the candidates are shown in a cols by rows grid
+const cols = 3
+const rows = 3
+
+const picWidth = winWidth / cols
+const picHeight = winHeight / rows
+
+// picture is a candidate of the current generation
+type picture struct {
+	tree     apt.Node
+	tex      *sdl.Texture
+	selected bool
+}
 
 func main() {
-	sdl.LogSetAllPriority(sdl.LOG_PRIORITY_VERBOSE)
+	seed := flag.Int64("seed", 0, "random seed, 0 picks one from the clock")
+	size := flag.Int("size", 20, "number of operators in the random trees")
+	depth := flag.Int("depth", 8, "maximum depth of the trees")
+	load := flag.String("load", "", "start from the tree saved in this file")
+	outDir := flag.String("out", ".", "directory the saved pictures are written to")
+	export := flag.String("export", "1920x1080", "size of the saved PNG pictures as WIDTHxHEIGHT")
+	flag.Parse()
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	fmt.Println("seed:", *seed)
+
+	var exportW, exportH int
+	if _, err := fmt.Sscanf(*export, "%dx%d", &exportW, &exportH); err != nil || exportW <= 0 || exportH <= 0 {
+		fmt.Println("Could not parse export size:", *export)
+		return
+	}
+
+	gen := apt.NewGenerator(*seed)
+	random := func() []apt.Node {
+		trees := make([]apt.Node, cols*rows)
+		for i := range trees {
+			trees[i] = gen.Tree(*size, *depth)
+		}
+		return trees
+	}
+	// the first generation is random unless it is bred from a loaded tree
+	first := random
+	if *load != "" {
+		b, err := ioutil.ReadFile(*load)
+		if err != nil {
+			fmt.Println("Could not read tree:", err)
+			return
+		}
+		tree, err := apt.Parse(string(b))
+		if err != nil {
+			fmt.Println("Could not parse tree:", err)
+			return
+		}
+		first = func() []apt.Node {
+			return append([]apt.Node{tree}, gen.Breed([]apt.Node{tree}, cols*rows-1, *depth)...)
+		}
+	}
+
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		panic(err)
 	}
 	defer sdl.Quit()
 
-	window, err := sdl.CreateWindow("Exploding Balloons", sdl.WINDOWPOS_UNDEFINED,
+	window, err := sdl.CreateWindow("Evolving pictures", sdl.WINDOWPOS_UNDEFINED,
 		sdl.WINDOWPOS_UNDEFINED, winWidth, winHeight, sdl.WINDOW_SHOWN)
 	if err != nil {
 		fmt.Println("Could not create window:", err)
@@ -39,84 +110,181 @@ func main() {
 	}
 	defer renderer.Destroy()
 
-	// var audioSpec sdl.AudioSpec
-	// deviceID, err := sdl.OpenAudioDevice("", false, &audioSpec, nil, 0)
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// explosionBytes, _ := sdl.LoadWAV("explode.wav")
-	// defer sdl.FreeWAV(explosionBytes)
-	// audioState := &balloon.AudioState{deviceID, audioSpec, explosionBytes}
-
-	sdl.SetHint(sdl.HINT_RENDER_SCALE_QUALITY, "1")
-
-	// imgs := loadImages("images", "balloon_")
-	// balloons := loadBalloons(renderer, imgs, 25)
+	// history holds the trees of every generation, the last one is shown
+	history := [][]apt.Node{first()}
+	var pictures []*picture
+	show := func() {
+		destroyPictures(pictures)
+		pictures = makePictures(renderer, history[len(history)-1])
+		window.SetTitle(fmt.Sprintf("Evolving pictures - generation %d", len(history)))
+	}
+	show()
 
-	currentMouseState := balloon.GetMouseState()
-	// previousMouseState := currentMouseState
-	var elapsedTime float32
 	running := true
-
-	x := apt.OpX{}
-	y := apt.OpY{}
-	sine := &apt.OpSin{}
-	plus := &apt.OpPlus{}
-
-	sine.Child = x
-	plus.LeftChild = sine
-	plus.RightChild = y
-
-	tex := aptToTexture(plus, renderer, 800, 600)
-
 	for running {
-		frameStart := time.Now()
-
-		currentMouseState = balloon.GetMouseState()
 		for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
 			switch e := event.(type) {
-			case *sdl.TouchFingerEvent:
-				if e.Type == sdl.FINGERDOWN {
-					touchX := int(e.X * float32(winWidth))
-					touchY := int(e.Y * float32(winHeight))
-					currentMouseState.X = touchX
-					currentMouseState.Y = touchY
-					currentMouseState.LeftButton = true
-				}
 			case *sdl.QuitEvent:
-				println("Quit")
 				running = false
-				break
+			case *sdl.MouseButtonEvent:
+				if e.Type == sdl.MOUSEBUTTONDOWN && e.Button == sdl.BUTTON_LEFT {
+					if p := pictureAt(pictures, e.X, e.Y); p != nil {
+						p.selected = !p.selected
+					}
+				}
+			case *sdl.KeyboardEvent:
+				if e.Type != sdl.KEYDOWN || e.Repeat != 0 {
+					break
+				}
+
+				switch e.Keysym.Sym {
+				case sdl.K_ESCAPE:
+					running = false
+				case sdl.K_SPACE, sdl.K_RETURN:
+					var favorites []apt.Node
+					for _, p := range pictures {
+						if p.selected {
+							favorites = append(favorites, p.tree)
+						}
+					}
+					if len(favorites) == 0 {
+						fmt.Println("Click the pictures you like before breeding")
+						break
+					}
+					history = append(history, nextGeneration(gen, favorites, *depth))
+					show()
+				case sdl.K_u, sdl.K_BACKSPACE:
+					if len(history) > 1 {
+						history = history[:len(history)-1]
+						show()
+					}
+				case sdl.K_r:
+					history = append(history, random())
+					show()
+				case sdl.K_s:
+					x, y, _ := sdl.GetMouseState()
+					i := pictureIndex(int32(x), int32(y))
+					if i < 0 {
+						break
+					}
+					name := filepath.Join(*outDir, fmt.Sprintf("%d-gen%d-%d", *seed, len(history), i))
+					if err := savePicture(name, pictures[i].tree, exportW, exportH); err != nil {
+						fmt.Println("Could not save picture:", err)
+						break
+					}
+					fmt.Println("Saved", name+".apt", "and", name+".png")
+				}
+			}
+		}
+
+		renderer.SetDrawColor(0, 0, 0, 255)
+		renderer.Clear()
+		for i, p := range pictures {
+			rect := pictureRect(i)
+			renderer.Copy(p.tex, nil, rect)
+			if p.selected {
+				renderer.SetDrawColor(255, 200, 0, 255)
+				for border := int32(0); border < 3; border++ {
+					renderer.DrawRect(&sdl.Rect{X: rect.X + border, Y: rect.Y + border,
+						W: rect.W - 2*border, H: rect.H - 2*border})
+				}
 			}
 		}
+		renderer.Present()
 
-		// if !currentMouseState.leftButton && previousMouseState.leftButton {
-		// 	fmt.Println("left click")
-		// }
+		sdl.Delay(16)
+	}
+	destroyPictures(pictures)
+}
 
-		renderer.Copy(tex, nil, nil)
+// nextGeneration keeps the favorites, up to half of the grid so there is
+// room for new pictures, and fills the rest with their children
+func nextGeneration(gen *apt.Generator, favorites []apt.Node, depth int) []apt.Node {
+	keep := len(favorites)
+	if keep > cols*rows/2 {
+		keep = cols * rows / 2
+	}
 
-		// balloon.UpdateBalloons(balloons, elapsedTime, currentMouseState,
-		// 	previousMouseState, audioState, winWidth, winHeight, winDepth)
-		// sort.Sort(balloon.Slice(balloons))
-		// for _, b := range balloons {
-		// 	b.Draw(renderer)
-		// }
+	trees := make([]apt.Node, 0, cols*rows)
+	trees = append(trees, favorites[:keep]...)
+	return append(trees, gen.Breed(favorites, cols*rows-keep, depth)...)
+}
 
-		renderer.Present()
+// pictureRect returns where the picture i of the grid is drawn
+func pictureRect(i int) *sdl.Rect {
+	return &sdl.Rect{
+		X: int32(i%cols) * picWidth,
+		Y: int32(i/cols) * picHeight,
+		W: picWidth,
+		H: picHeight,
+	}
+}
 
-		elapsedTime = float32(time.Since(frameStart).Milliseconds())
-		// // fmt.Println("ms per frame", elapsedTime)
-		if elapsedTime < 5 {
-			sdl.Delay(5 - uint32(elapsedTime))
-			elapsedTime = float32(time.Since(frameStart).Milliseconds())
-		}
+// pictureIndex returns the index of the picture at x, y, or -1
+func pictureIndex(x, y int32) int {
+	col, row := int(x/picWidth), int(y/picHeight)
+	if x < 0 || y < 0 || col >= cols || row >= rows {
+		return -1
+	}
+	return row*cols + col
+}
 
-		// previousMouseState = currentMouseState
+func pictureAt(pictures []*picture, x, y int32) *picture {
+	if i := pictureIndex(x, y); i >= 0 && i < len(pictures) {
+		return pictures[i]
 	}
+	return nil
 }
 
-func aptToTexture(node apt.Node, renderer *sdl.Renderer, w, h int) *sdl.Texture {
+// makePictures renders the trees, one goroutine per tree
+func makePictures(renderer *sdl.Renderer, trees []apt.Node) []*picture {
+	pixels := make([][]byte, len(trees))
+	var wg sync.WaitGroup
+	for i, tree := range trees {
+		wg.Add(1)
+		go func(i int, tree apt.Node) {
+			defer wg.Done()
+			pixels[i] = aptToPixels(tree, picWidth, picHeight)
+		}(i, tree)
+	}
+	wg.Wait()
+
+	// textures must be created on the thread that owns the renderer
+	pictures := make([]*picture, len(trees))
+	for i, tree := range trees {
+		pictures[i] = &picture{tree: tree, tex: pixelsToTexture(renderer, pixels[i], picWidth, picHeight)}
+	}
+	return pictures
+}
+
+func destroyPictures(pictures []*picture) {
+	for _, p := range pictures {
+		p.tex.Destroy()
+	}
+}
+
+// savePicture writes the tree to name.apt and renders it to the w by h
+// PNG name.png
+func savePicture(name string, tree apt.Node, w, h int) error {
+	if err := ioutil.WriteFile(name+".apt", []byte(tree.String()+"\n"), 0644); err != nil {
+		return err
+	}
+
+	img := &image.RGBA{Pix: aptToPixels(tree, w, h), Stride: w * 4, Rect: image.Rect(0, 0, w, h)}
+	f, err := os.Create(name + ".png")
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// aptToPixels evaluates the tree over [-1,1] on both axes and maps its
+// [-1,1] output to gray RGBA pixels
+func aptToPixels(node apt.Node, w, h int) []byte {
 	pixels := make([]byte, w*h*4)
 	scale := float32(255 / 2)
 	offset := float32(-1.0 * scale)
@@ -126,15 +294,27 @@ func aptToTexture(node apt.Node, renderer *sdl.Renderer, w, h int) *sdl.Texture
 		y := float32(yi)/float32(h)*2 - 1
 		for xi := 0; xi < w; xi++ {
 			x := float32(xi)/float32(w)*2 - 1
-			c := node.Eval(x, y)
-			pixels[i] = byte(c*scale - offset)
-			pixels[i+1] = byte(c*scale - offset)
-			pixels[i+2] = byte(c*scale - offset)
+			c := clamp(0, 255, node.Eval(x, y)*scale-offset)
+			pixels[i] = byte(c)
+			pixels[i+1] = byte(c)
+			pixels[i+2] = byte(c)
+			pixels[i+3] = 255
 			i += 4
 		}
 	}
 
-	return pixelsToTexture(renderer, pixels, w, h)
+	return pixels
+}
+
+// clamp ensures v is within [min, max]. NaN gives min.
+func clamp(min, max, v float32) float32 {
+	if v > max {
+		return max
+	}
+	if v >= min {
+		return v
+	}
+	return min
 }
 
 func pixelsToTexture(renderer *sdl.Renderer, pixels []byte, w, h int) *sdl.Texture {
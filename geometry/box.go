@@ -0,0 +1,131 @@
+package geometry
+
+import "github.com/dikaeinstein/games-with-go/vector"
+
+// AABB is an axis aligned box spanning Min to Max
+type AABB struct {
+	Min, Max vector.Vec2
+}
+
+// RectAABB returns the w by h box centered on center
+func RectAABB(center vector.Vec2, w, h float32) AABB {
+	half := vector.Vec2{X: w / 2, Y: h / 2}
+	return AABB{center.Sub(half), center.Add(half)}
+}
+
+// Center returns the center of the box
+func (b AABB) Center() vector.Vec2 {
+	return b.Min.Lerp(b.Max, 0.5)
+}
+
+// Size returns the width and height of the box
+func (b AABB) Size() vector.Vec2 {
+	return b.Max.Sub(b.Min)
+}
+
+// Contains reports whether p is inside the box
+func (b AABB) Contains(p vector.Vec2) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X && p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+// ClosestPoint returns the point of the box closest to p, which is p
+// itself when it is inside
+func (b AABB) ClosestPoint(p vector.Vec2) vector.Vec2 {
+	return vector.Vec2{X: clamp(p.X, b.Min.X, b.Max.X), Y: clamp(p.Y, b.Min.Y, b.Max.Y)}
+}
+
+// Polygon returns the corners of the box
+func (b AABB) Polygon() Polygon {
+	return Polygon{[]vector.Vec2{
+		b.Min,
+		{X: b.Max.X, Y: b.Min.Y},
+		b.Max,
+		{X: b.Min.X, Y: b.Max.Y},
+	}}
+}
+
+// closestSide returns the outward normal of the side of the box closest
+// to p, which is inside the box, and the distance to that side
+func (b AABB) closestSide(p vector.Vec2) (vector.Vec2, float32) {
+	normal, dist := vector.Vec2{X: -1}, p.X-b.Min.X
+	if d := b.Max.X - p.X; d < dist {
+		normal, dist = vector.Vec2{X: 1}, d
+	}
+	if d := p.Y - b.Min.Y; d < dist {
+		normal, dist = vector.Vec2{Y: -1}, d
+	}
+	if d := b.Max.Y - p.Y; d < dist {
+		normal, dist = vector.Vec2{Y: 1}, d
+	}
+	return normal, dist
+}
+
+// AABBAABB tests whether the boxes a and b overlap
+func AABBAABB(a, b AABB) (Contact, bool) {
+	dx, ok := overlap(a.Min.X, a.Max.X, b.Min.X, b.Max.X)
+	if !ok {
+		return Contact{}, false
+	}
+	dy, ok := overlap(a.Min.Y, a.Max.Y, b.Min.Y, b.Max.Y)
+	if !ok {
+		return Contact{}, false
+	}
+
+	d := b.Center().Sub(a.Center())
+	if dx < dy {
+		if d.X < 0 {
+			return Contact{vector.Vec2{X: -1}, dx}, true
+		}
+		return Contact{vector.Vec2{X: 1}, dx}, true
+	}
+	if d.Y < 0 {
+		return Contact{vector.Vec2{Y: -1}, dy}, true
+	}
+	return Contact{vector.Vec2{Y: 1}, dy}, true
+}
+
+// OBB is a box with the given center and half extents, rotated
+// counter-clockwise by Angle radians around its center
+type OBB struct {
+	Center      vector.Vec2
+	HalfExtents vector.Vec2
+	Angle       float32
+}
+
+func (b OBB) rotation() vector.Mat3 {
+	return vector.Rotation3(b.Angle)
+}
+
+// toLocal returns p in the frame of the box, where it is centered on the
+// origin and aligned with the axes
+func (b OBB) toLocal(p vector.Vec2) vector.Vec2 {
+	return vector.Rotation3(-b.Angle).MulDir(p.Sub(b.Center))
+}
+
+// Contains reports whether p is inside the box
+func (b OBB) Contains(p vector.Vec2) bool {
+	l := b.toLocal(p)
+	return abs(l.X) <= b.HalfExtents.X && abs(l.Y) <= b.HalfExtents.Y
+}
+
+// Polygon returns the corners of the box
+func (b OBB) Polygon() Polygon {
+	m := vector.Translation3(b.Center).Mul(b.rotation())
+	h := b.HalfExtents
+	return Polygon{[]vector.Vec2{
+		m.MulPoint(vector.Vec2{X: -h.X, Y: -h.Y}),
+		m.MulPoint(vector.Vec2{X: h.X, Y: -h.Y}),
+		m.MulPoint(vector.Vec2{X: h.X, Y: h.Y}),
+		m.MulPoint(vector.Vec2{X: -h.X, Y: h.Y}),
+	}}
+}
+
+// Bounds returns the smallest AABB holding the box
+func (b OBB) Bounds() AABB {
+	return b.Polygon().Bounds()
+}
+
+// OBBOBB tests whether the oriented boxes a and b overlap
+func OBBOBB(a, b OBB) (Contact, bool) {
+	return PolygonPolygon(a.Polygon(), b.Polygon())
+}
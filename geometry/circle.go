@@ -0,0 +1,143 @@
+package geometry
+
+import "github.com/dikaeinstein/games-with-go/vector"
+
+// Circle is a disc with the given center and radius
+type Circle struct {
+	Center vector.Vec2
+	Radius float32
+}
+
+// Contains reports whether p is inside the circle
+func (c Circle) Contains(p vector.Vec2) bool {
+	return c.Center.DistanceSquared(p) < c.Radius*c.Radius
+}
+
+// Bounds returns the smallest AABB holding the circle
+func (c Circle) Bounds() AABB {
+	r := vector.Vec2{X: c.Radius, Y: c.Radius}
+	return AABB{c.Center.Sub(r), c.Center.Add(r)}
+}
+
+// CircleCircle tests whether the circles a and b overlap
+func CircleCircle(a, b Circle) (Contact, bool) {
+	d := b.Center.Sub(a.Center)
+	r := a.Radius + b.Radius
+	distSq := d.LengthSquared()
+	if distSq >= r*r {
+		return Contact{}, false
+	}
+
+	dist := sqrt(distSq)
+	if dist == 0 {
+		// concentric circles, any direction separates them
+		return Contact{vector.Vec2{X: 1}, r}, true
+	}
+	return Contact{d.Scale(1 / dist), r - dist}, true
+}
+
+// CircleAABB tests whether the circle c and the box overlap
+func CircleAABB(c Circle, box AABB) (Contact, bool) {
+	closest := box.ClosestPoint(c.Center)
+	d := closest.Sub(c.Center)
+	distSq := d.LengthSquared()
+	if distSq >= c.Radius*c.Radius {
+		return Contact{}, false
+	}
+	if distSq > 0 {
+		dist := sqrt(distSq)
+		return Contact{d.Scale(1 / dist), c.Radius - dist}, true
+	}
+
+	// the center is inside the box, push it out through the closest side
+	normal, dist := box.closestSide(c.Center)
+	return Contact{normal.Scale(-1), c.Radius + dist}, true
+}
+
+// CircleOBB tests whether the circle c and the oriented box overlap
+func CircleOBB(c Circle, box OBB) (Contact, bool) {
+	local := Circle{box.toLocal(c.Center), c.Radius}
+	contact, ok := CircleAABB(local, AABB{box.HalfExtents.Scale(-1), box.HalfExtents})
+	if !ok {
+		return Contact{}, false
+	}
+	contact.Normal = box.rotation().MulDir(contact.Normal)
+	return contact, true
+}
+
+// CircleSegment tests whether the circle c and the segment s overlap
+func CircleSegment(c Circle, s Segment) (Contact, bool) {
+	d := s.ClosestPoint(c.Center).Sub(c.Center)
+	distSq := d.LengthSquared()
+	if distSq >= c.Radius*c.Radius {
+		return Contact{}, false
+	}
+
+	dist := sqrt(distSq)
+	if dist == 0 {
+		// the center is on the segment, push it out along its normal
+		return Contact{s.Normal().Scale(-1), c.Radius}, true
+	}
+	return Contact{d.Scale(1 / dist), c.Radius - dist}, true
+}
+
+// CirclePolygon tests whether the circle c and the convex polygon p
+// overlap, using the separating axis theorem
+func CirclePolygon(c Circle, p Polygon) (Contact, bool) {
+	if len(p.Points) == 0 {
+		return Contact{}, false
+	}
+
+	axes := p.axes()
+	// the axis from the closest vertex catches the circle past a corner
+	closest := p.Points[0]
+	for _, v := range p.Points[1:] {
+		if v.DistanceSquared(c.Center) < closest.DistanceSquared(c.Center) {
+			closest = v
+		}
+	}
+	if axis := closest.Sub(c.Center).Normalize(); axis.LengthSquared() > 0 {
+		axes = append(axes, axis)
+	}
+
+	best := Contact{Depth: -1}
+	for _, axis := range axes {
+		center := c.Center.Dot(axis)
+		minA, maxA := center-c.Radius, center+c.Radius
+		minB, maxB := p.project(axis)
+		depth, ok := overlap(minA, maxA, minB, maxB)
+		if !ok {
+			return Contact{}, false
+		}
+		if best.Depth < 0 || depth < best.Depth {
+			best = Contact{axis, depth}
+		}
+	}
+
+	if best.Normal.Dot(p.Centroid().Sub(c.Center)) < 0 {
+		best.Normal = best.Normal.Scale(-1)
+	}
+	return best, true
+}
+
+// RayCircle returns where the ray r enters the circle c. A ray starting
+// inside the circle hits it at T 0.
+func RayCircle(r Ray, c Circle) (Hit, bool) {
+	m := r.Origin.Sub(c.Center)
+	if m.LengthSquared() < c.Radius*c.Radius {
+		return Hit{0, r.Origin, r.Dir.Scale(-1).Normalize()}, true
+	}
+
+	// solve |m + t*dir| = radius for the smallest t
+	a := r.Dir.LengthSquared()
+	b := m.Dot(r.Dir)
+	cc := m.LengthSquared() - c.Radius*c.Radius
+	disc := b*b - a*cc
+	if a == 0 || b > 0 || disc < 0 {
+		return Hit{}, false
+	}
+
+	t := (-b - sqrt(disc)) / a
+	p := r.At(t)
+	return Hit{t, p, p.Sub(c.Center).Normalize()}, true
+}
@@ -0,0 +1,52 @@
+// Package geometry provides 2D and 3D shapes and the intersection tests
+// between them that games need for collisions and hit testing.
+//
+// The tests between two shapes a and b return a Contact whose Normal points
+// from a towards b, so moving b by Normal*Depth, or a by -Normal*Depth,
+// separates them.
+package geometry
+
+import (
+	"math"
+
+	"github.com/dikaeinstein/games-with-go/vector"
+)
+
+// Contact describes how two overlapping shapes touch
+type Contact struct {
+	// Normal is the unit direction from the first shape to the second
+	Normal vector.Vec2
+	// Depth is how far the shapes overlap along Normal
+	Depth float32
+}
+
+// Hit describes where a ray first meets a shape
+type Hit struct {
+	// T is the distance along the ray in units of its direction, so the
+	// hit point is Origin + Dir*T
+	T float32
+	// Point is where the ray meets the shape
+	Point vector.Vec2
+	// Normal is the unit normal of the shape surface at Point
+	Normal vector.Vec2
+}
+
+func clamp(v, min, max float32) float32 {
+	if v < min {
+		return min
+	} else if v > max {
+		return max
+	}
+	return v
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sqrt(v float32) float32 {
+	return float32(math.Sqrt(float64(v)))
+}
@@ -0,0 +1,117 @@
+package geometry
+
+import "github.com/dikaeinstein/games-with-go/vector"
+
+// Polygon is a convex polygon with its points in order around it, in
+// either direction
+type Polygon struct {
+	Points []vector.Vec2
+}
+
+// Centroid returns the average of the polygon points
+func (p Polygon) Centroid() vector.Vec2 {
+	var sum vector.Vec2
+	for _, v := range p.Points {
+		sum = sum.Add(v)
+	}
+	if len(p.Points) == 0 {
+		return sum
+	}
+	return sum.Scale(1 / float32(len(p.Points)))
+}
+
+// Bounds returns the smallest AABB holding the polygon
+func (p Polygon) Bounds() AABB {
+	if len(p.Points) == 0 {
+		return AABB{}
+	}
+	b := AABB{p.Points[0], p.Points[0]}
+	for _, v := range p.Points[1:] {
+		b.Min = b.Min.Min(v)
+		b.Max = b.Max.Max(v)
+	}
+	return b
+}
+
+// Contains reports whether v is inside the polygon
+func (p Polygon) Contains(v vector.Vec2) bool {
+	var sign float32
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		side := b.Sub(a).Cross(v.Sub(a))
+		if side == 0 {
+			continue
+		}
+		if sign == 0 {
+			sign = side
+		} else if (side > 0) != (sign > 0) {
+			return false
+		}
+	}
+	return len(p.Points) > 0
+}
+
+// axes returns the unit normals of the polygon edges
+func (p Polygon) axes() []vector.Vec2 {
+	axes := make([]vector.Vec2, 0, len(p.Points))
+	for i, a := range p.Points {
+		b := p.Points[(i+1)%len(p.Points)]
+		if axis := b.Sub(a).Perp().Normalize(); axis.LengthSquared() > 0 {
+			axes = append(axes, axis)
+		}
+	}
+	return axes
+}
+
+// project returns the interval the polygon covers along axis
+func (p Polygon) project(axis vector.Vec2) (min, max float32) {
+	for i, v := range p.Points {
+		d := v.Dot(axis)
+		if i == 0 || d < min {
+			min = d
+		}
+		if i == 0 || d > max {
+			max = d
+		}
+	}
+	return min, max
+}
+
+// overlap returns how much the intervals [minA, maxA] and [minB, maxB]
+// overlap, and false if they do not
+func overlap(minA, maxA, minB, maxB float32) (float32, bool) {
+	if maxA <= minB || maxB <= minA {
+		return 0, false
+	}
+	d := maxA - minB
+	if d2 := maxB - minA; d2 < d {
+		d = d2
+	}
+	return d, true
+}
+
+// PolygonPolygon tests whether the convex polygons a and b overlap, using
+// the separating axis theorem
+func PolygonPolygon(a, b Polygon) (Contact, bool) {
+	if len(a.Points) == 0 || len(b.Points) == 0 {
+		return Contact{}, false
+	}
+
+	best := Contact{Depth: -1}
+	for _, axis := range append(a.axes(), b.axes()...) {
+		minA, maxA := a.project(axis)
+		minB, maxB := b.project(axis)
+		depth, ok := overlap(minA, maxA, minB, maxB)
+		if !ok {
+			return Contact{}, false
+		}
+		if best.Depth < 0 || depth < best.Depth {
+			best = Contact{axis, depth}
+		}
+	}
+
+	if best.Normal.Dot(b.Centroid().Sub(a.Centroid())) < 0 {
+		best.Normal = best.Normal.Scale(-1)
+	}
+	return best, true
+}
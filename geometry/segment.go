@@ -0,0 +1,125 @@
+package geometry
+
+import "github.com/dikaeinstein/games-with-go/vector"
+
+// Segment is the line segment from A to B
+type Segment struct {
+	A, B vector.Vec2
+}
+
+// Normal returns the unit normal of the segment, a quarter turn
+// counter-clockwise from the direction A to B
+func (s Segment) Normal() vector.Vec2 {
+	return s.B.Sub(s.A).Perp().Normalize()
+}
+
+// ClosestPoint returns the point of the segment closest to p
+func (s Segment) ClosestPoint(p vector.Vec2) vector.Vec2 {
+	ab := s.B.Sub(s.A)
+	lenSq := ab.LengthSquared()
+	if lenSq == 0 {
+		return s.A
+	}
+	t := clamp(p.Sub(s.A).Dot(ab)/lenSq, 0, 1)
+	return s.A.Add(ab.Scale(t))
+}
+
+// SegmentSegment returns the point where the segments a and b cross.
+// Collinear segments are not reported as crossing.
+func SegmentSegment(a, b Segment) (vector.Vec2, bool) {
+	r := a.B.Sub(a.A)
+	s := b.B.Sub(b.A)
+	denom := r.Cross(s)
+	if denom == 0 {
+		return vector.Vec2{}, false
+	}
+
+	ab := b.A.Sub(a.A)
+	t := ab.Cross(s) / denom
+	u := ab.Cross(r) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return vector.Vec2{}, false
+	}
+	return a.A.Add(r.Scale(t)), true
+}
+
+// Ray is the half line starting at Origin going towards Dir
+type Ray struct {
+	Origin, Dir vector.Vec2
+}
+
+// At returns the point at distance t along the ray, in units of Dir
+func (r Ray) At(t float32) vector.Vec2 {
+	return r.Origin.Add(r.Dir.Scale(t))
+}
+
+// RaySegment returns where the ray r crosses the segment s. The normal
+// faces the ray origin.
+func RaySegment(r Ray, s Segment) (Hit, bool) {
+	e := s.B.Sub(s.A)
+	denom := r.Dir.Cross(e)
+	if denom == 0 {
+		return Hit{}, false
+	}
+
+	d := s.A.Sub(r.Origin)
+	t := d.Cross(e) / denom
+	u := d.Cross(r.Dir) / denom
+	if t < 0 || u < 0 || u > 1 {
+		return Hit{}, false
+	}
+
+	normal := s.Normal()
+	if normal.Dot(r.Dir) > 0 {
+		normal = normal.Scale(-1)
+	}
+	return Hit{t, r.At(t), normal}, true
+}
+
+// RayAABB returns where the ray r enters the box. A ray starting inside
+// the box hits it at T 0.
+func RayAABB(r Ray, box AABB) (Hit, bool) {
+	if box.Contains(r.Origin) {
+		return Hit{0, r.Origin, r.Dir.Scale(-1).Normalize()}, true
+	}
+
+	// clip the ray against the slabs between the sides of each axis
+	tMin, tMax := float32(0), float32(0)
+	var normal vector.Vec2
+	first := true
+	slabs := [2]struct {
+		origin, dir, min, max float32
+		axis                  vector.Vec2
+	}{
+		{r.Origin.X, r.Dir.X, box.Min.X, box.Max.X, vector.Vec2{X: 1}},
+		{r.Origin.Y, r.Dir.Y, box.Min.Y, box.Max.Y, vector.Vec2{Y: 1}},
+	}
+	for _, s := range slabs {
+		if s.dir == 0 {
+			if s.origin < s.min || s.origin > s.max {
+				return Hit{}, false
+			}
+			continue
+		}
+
+		t1 := (s.min - s.origin) / s.dir
+		t2 := (s.max - s.origin) / s.dir
+		n := s.axis.Scale(-1)
+		if t1 > t2 {
+			t1, t2 = t2, t1
+			n = s.axis
+		}
+		if first || t1 > tMin {
+			tMin, normal = t1, n
+		}
+		if first || t2 < tMax {
+			tMax = t2
+		}
+		first = false
+	}
+
+	if first || tMin > tMax || tMin < 0 {
+		return Hit{}, false
+	}
+	return Hit{tMin, r.At(tMin), normal}, true
+}
@@ -0,0 +1,74 @@
+package geometry
+
+import "github.com/dikaeinstein/games-with-go/vector"
+
+// Sphere is a ball with the given center and radius
+type Sphere struct {
+	Center vector.Vector
+	Radius float32
+}
+
+// Contact3 describes how two overlapping 3D shapes touch, see Contact
+type Contact3 struct {
+	Normal vector.Vector
+	Depth  float32
+}
+
+// Ray3 is the 3D half line starting at Origin going towards Dir
+type Ray3 struct {
+	Origin, Dir vector.Vector
+}
+
+// At returns the point at distance t along the ray, in units of Dir
+func (r Ray3) At(t float32) vector.Vector {
+	return r.Origin.Add(r.Dir.Scale(t))
+}
+
+// Hit3 describes where a 3D ray first meets a shape, see Hit
+type Hit3 struct {
+	T      float32
+	Point  vector.Vector
+	Normal vector.Vector
+}
+
+// Contains reports whether p is inside the sphere
+func (s Sphere) Contains(p vector.Vector) bool {
+	return s.Center.DistanceSquared(p) < s.Radius*s.Radius
+}
+
+// SphereSphere tests whether the spheres a and b overlap
+func SphereSphere(a, b Sphere) (Contact3, bool) {
+	d := b.Center.Sub(a.Center)
+	r := a.Radius + b.Radius
+	distSq := d.LengthSquared()
+	if distSq >= r*r {
+		return Contact3{}, false
+	}
+
+	dist := sqrt(distSq)
+	if dist == 0 {
+		return Contact3{vector.Vector{X: 1}, r}, true
+	}
+	return Contact3{d.Scale(1 / dist), r - dist}, true
+}
+
+// RaySphere returns where the ray r enters the sphere s. A ray starting
+// inside the sphere hits it at T 0.
+func RaySphere(r Ray3, s Sphere) (Hit3, bool) {
+	m := r.Origin.Sub(s.Center)
+	if m.LengthSquared() < s.Radius*s.Radius {
+		return Hit3{0, r.Origin, r.Dir.Scale(-1).Normalize()}, true
+	}
+
+	a := r.Dir.LengthSquared()
+	b := m.Dot(r.Dir)
+	c := m.LengthSquared() - s.Radius*s.Radius
+	disc := b*b - a*c
+	if a == 0 || b > 0 || disc < 0 {
+		return Hit3{}, false
+	}
+
+	t := (-b - sqrt(disc)) / a
+	p := r.At(t)
+	return Hit3{t, p, p.Sub(s.Center).Normalize()}, true
+}
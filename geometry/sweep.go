@@ -0,0 +1,90 @@
+package geometry
+
+import "github.com/dikaeinstein/games-with-go/vector"
+
+// The swept tests move a circle by move and return the first time it
+// touches an obstacle, so fast objects cannot jump over thin ones between
+// two frames. The Hit T is the fraction of move travelled before the
+// contact, in [0,1], Point is the circle center at that time and Normal
+// points from the obstacle towards the circle. A circle already
+// overlapping the obstacle hits it at T 0, with Point moved out of it,
+// while a circle just touching it and moving away does not hit it.
+
+// SweepCircleAABB sweeps the circle c by move against the box
+func SweepCircleAABB(c Circle, move vector.Vec2, box AABB) (Hit, bool) {
+	if contact, ok := CircleAABB(c, box); ok {
+		return separate(c, contact), true
+	}
+
+	// the circle touches the box when its center enters the box grown by
+	// the radius, whose corners are rounded
+	r := vector.Vec2{X: c.Radius, Y: c.Radius}
+	ray := Ray{c.Center, move}
+	hit, ok := RayAABB(ray, AABB{box.Min.Sub(r), box.Max.Add(r)})
+	if !ok || hit.T > 1 {
+		return Hit{}, false
+	}
+
+	p := hit.Point
+	outX := p.X < box.Min.X || p.X > box.Max.X
+	outY := p.Y < box.Min.Y || p.Y > box.Max.Y
+	if outX && outY {
+		corner := box.Min
+		if p.X > box.Max.X {
+			corner.X = box.Max.X
+		}
+		if p.Y > box.Max.Y {
+			corner.Y = box.Max.Y
+		}
+		hit, ok = RayCircle(ray, Circle{corner, c.Radius})
+		if !ok || hit.T > 1 {
+			return Hit{}, false
+		}
+	}
+	return approach(hit, move, box.ClosestPoint(hit.Point))
+}
+
+// SweepCircleSegment sweeps the circle c by move against the segment s
+func SweepCircleSegment(c Circle, move vector.Vec2, s Segment) (Hit, bool) {
+	if contact, ok := CircleSegment(c, s); ok {
+		return separate(c, contact), true
+	}
+
+	// the circle touches the segment when its center enters the capsule
+	// around it: two sides parallel to the segment and a circle at each end
+	ray := Ray{c.Center, move}
+	best, found := Hit{}, false
+	keep := func(h Hit, ok bool) {
+		if ok && h.T <= 1 && (!found || h.T < best.T) {
+			h, ok = approach(h, move, s.ClosestPoint(h.Point))
+			if ok {
+				best, found = h, true
+			}
+		}
+	}
+
+	n := s.Normal().Scale(c.Radius)
+	keep(RaySegment(ray, Segment{s.A.Add(n), s.B.Add(n)}))
+	keep(RaySegment(ray, Segment{s.A.Sub(n), s.B.Sub(n)}))
+	keep(RayCircle(ray, Circle{s.A, c.Radius}))
+	keep(RayCircle(ray, Circle{s.B, c.Radius}))
+	return best, found
+}
+
+// separate returns the hit at T 0 of the circle c overlapping an obstacle
+// with the given contact
+func separate(c Circle, contact Contact) Hit {
+	normal := contact.Normal.Scale(-1)
+	return Hit{0, c.Center.Add(normal.Scale(contact.Depth)), normal}
+}
+
+// approach sets the normal of hit to point from the closest point of the
+// obstacle to the circle center, and drops the hit if move does not go
+// towards the obstacle, as when the circle is touching it and moving away
+func approach(hit Hit, move, closest vector.Vec2) (Hit, bool) {
+	hit.Normal = hit.Point.Sub(closest).Normalize()
+	if move.Dot(hit.Normal) >= 0 {
+		return Hit{}, false
+	}
+	return hit, true
+}
@@ -0,0 +1,133 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/dikaeinstein/games-with-go/vector"
+)
+
+func near(a, b float32) bool {
+	return abs(a-b) < 1e-4
+}
+
+func nearVec(a, b vector.Vec2) bool {
+	return near(a.X, b.X) && near(a.Y, b.Y)
+}
+
+type sweepTest struct {
+	name string
+	c    Circle
+	move vector.Vec2
+	want Hit
+	hit  bool
+}
+
+func checkSweep(t *testing.T, tt sweepTest, got Hit, ok bool) {
+	t.Helper()
+	if ok != tt.hit {
+		t.Fatalf("hit = %v, want %v (%+v)", ok, tt.hit, got)
+	}
+	if ok && (!near(got.T, tt.want.T) || !nearVec(got.Point, tt.want.Point) || !nearVec(got.Normal, tt.want.Normal)) {
+		t.Errorf("got %+v, want %+v", got, tt.want)
+	}
+}
+
+func TestSweepCircleAABB(t *testing.T) {
+	// a thin paddle, 10 wide and 100 high
+	box := AABB{Min: vector.Vec2{X: 100, Y: 0}, Max: vector.Vec2{X: 110, Y: 100}}
+
+	tests := []sweepTest{
+		{
+			// the move ends far past the paddle, which a test of the end
+			// position alone would miss
+			name: "tunnelling",
+			c:    Circle{Center: vector.Vec2{X: 50, Y: 50}, Radius: 5},
+			move: vector.Vec2{X: 200, Y: 0},
+			want: Hit{T: 0.225, Point: vector.Vec2{X: 95, Y: 50}, Normal: vector.Vec2{X: -1, Y: 0}},
+			hit:  true,
+		},
+		{
+			name: "rounded corner hit",
+			c:    Circle{Center: vector.Vec2{X: 50, Y: -4}, Radius: 5},
+			move: vector.Vec2{X: 100, Y: 0},
+			want: Hit{T: 0.47, Point: vector.Vec2{X: 97, Y: -4}, Normal: vector.Vec2{X: -0.6, Y: -0.8}},
+			hit:  true,
+		},
+		{
+			// the center crosses the square corner of the box grown by the
+			// radius, but stays more than the radius away from the corner
+			name: "rounded corner miss",
+			c:    Circle{Center: vector.Vec2{X: 90.5, Y: 0.5}, Radius: 5},
+			move: vector.Vec2{X: 20, Y: -20},
+		},
+		{
+			name: "overlapping start",
+			c:    Circle{Center: vector.Vec2{X: 97, Y: 50}, Radius: 5},
+			move: vector.Vec2{X: 10, Y: 0},
+			want: Hit{T: 0, Point: vector.Vec2{X: 95, Y: 50}, Normal: vector.Vec2{X: -1, Y: 0}},
+			hit:  true,
+		},
+		{
+			name: "touching and moving away",
+			c:    Circle{Center: vector.Vec2{X: 95, Y: 50}, Radius: 5},
+			move: vector.Vec2{X: -10, Y: 0},
+		},
+		{
+			name: "stops short",
+			c:    Circle{Center: vector.Vec2{X: 50, Y: 50}, Radius: 5},
+			move: vector.Vec2{X: 40, Y: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SweepCircleAABB(tt.c, tt.move, box)
+			checkSweep(t, tt, got, ok)
+		})
+	}
+}
+
+func TestSweepCircleSegment(t *testing.T) {
+	wall := Segment{A: vector.Vec2{X: 0, Y: 0}, B: vector.Vec2{X: 200, Y: 0}}
+
+	tests := []sweepTest{
+		{
+			name: "tunnelling",
+			c:    Circle{Center: vector.Vec2{X: 50, Y: 50}, Radius: 5},
+			move: vector.Vec2{X: 0, Y: -200},
+			want: Hit{T: 0.225, Point: vector.Vec2{X: 50, Y: 5}, Normal: vector.Vec2{X: 0, Y: 1}},
+			hit:  true,
+		},
+		{
+			name: "rounded end hit",
+			c:    Circle{Center: vector.Vec2{X: -4, Y: 50}, Radius: 5},
+			move: vector.Vec2{X: 0, Y: -100},
+			want: Hit{T: 0.47, Point: vector.Vec2{X: -4, Y: 3}, Normal: vector.Vec2{X: -0.8, Y: 0.6}},
+			hit:  true,
+		},
+		{
+			name: "rounded end miss",
+			c:    Circle{Center: vector.Vec2{X: -5.5, Y: 50}, Radius: 5},
+			move: vector.Vec2{X: 0, Y: -100},
+		},
+		{
+			name: "overlapping start",
+			c:    Circle{Center: vector.Vec2{X: 50, Y: 3}, Radius: 5},
+			move: vector.Vec2{X: 0, Y: -10},
+			want: Hit{T: 0, Point: vector.Vec2{X: 50, Y: 5}, Normal: vector.Vec2{X: 0, Y: 1}},
+			hit:  true,
+		},
+		{
+			name: "touching and moving away",
+			c:    Circle{Center: vector.Vec2{X: 50, Y: 5}, Radius: 5},
+			move: vector.Vec2{X: 0, Y: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SweepCircleSegment(tt.c, tt.move, wall)
+			checkSweep(t, tt, got, ok)
+		})
+	}
+}
@@ -2,12 +2,19 @@ package noise
 
 import (
 	"math"
+	"runtime"
+	"sync"
 
 	"github.com/dikaeinstein/games-with-go/simplex"
 )
 
-// Noise is a slice of 2D simplex noise values
-type Noise []float32
+// Field is a 2D grid of noise values stored row by row, along with the
+// smallest and largest value it holds
+type Field struct {
+	W, H     int
+	Values   []float32
+	Min, Max float32
+}
 
 // Type indicates which noise MakeNoise will generate
 type Type uint
@@ -17,96 +24,217 @@ const (
 	FBM Type = iota
 	// TURBULENCE is the turbulence fractal noise
 	TURBULENCE
+	// RIDGED is Musgrave's ridged multifractal noise
+	RIDGED
+	// HYBRID is Musgrave's hybrid multifractal noise
+	HYBRID
+	// CELLULAR is the fractal brownian motion of Worley noise
+	CELLULAR
 )
 
-// MakeNoise creates a slice 2D simplex Noise
-func MakeNoise(noiseType Type, w, h int, frequency, lacunarity, gain float32, octaves int) Noise {
+// Params holds the fractal parameters used to generate a noise Field
+type Params struct {
+	Frequency  float32
+	Lacunarity float32
+	Gain       float32
+	Octaves    int
+	// Offset and H are only used by the RIDGED and HYBRID types.
+	// H is the fractal increment, the higher it is the faster the
+	// octaves fade out.
+	Offset float32
+	H      float32
+	// TileW and TileH make the noise wrap around every TileW units along
+	// x and every TileH units along y. Zero disables wrapping on that axis.
+	TileW, TileH int
+	// Worley configures the CELLULAR type
+	Worley Worley
+	// Generator is the simplex noise generator of the other types. When
+	// nil, the default permutation table of the simplex package is used.
+	Generator *simplex.Generator
+}
+
+// DefaultParams returns the given fractal parameters completed with
+// Musgrave's suggested offset and H for the noise type
+func DefaultParams(noiseType Type, frequency, lacunarity, gain float32, octaves int) Params {
+	p := Params{
+		Frequency:  frequency,
+		Lacunarity: lacunarity,
+		Gain:       gain,
+		Octaves:    octaves,
+		Offset:     1.0,
+		H:          1.0,
+	}
+	if noiseType == HYBRID {
+		p.Offset = 0.7
+		p.H = 0.25
+	}
+	return p
+}
+
+// MakeNoise creates a w by h Field of 2D simplex noise using the default
+// offset and H of the noise type, see DefaultParams
+func MakeNoise(noiseType Type, w, h int, frequency, lacunarity, gain float32, octaves int) *Field {
+	return MakeField(noiseType, w, h, DefaultParams(noiseType, frequency, lacunarity, gain, octaves))
+}
+
+// MakeField creates a w by h Field of 2D simplex noise
+func MakeField(noiseType Type, w, h int, p Params) *Field {
+	return makeField(w, h, runtime.NumCPU(), fieldEval(noiseType, p))
+}
+
+func fieldEval(noiseType Type, p Params) func(x, y float32) float32 {
+	return func(x, y float32) float32 {
+		return Eval(noiseType, x, y, p)
+	}
+}
+
+// makeField creates a w by h Field by sampling eval at every point. The
+// rows are split between up to the given number of workers, each tracking
+// the min and max of its own rows, so the result is the same as generating
+// the rows in order.
+func makeField(w, h, workers int, eval func(x, y float32) float32) *Field {
 	noise := make([]float32, w*h)
 
-	// numCPUs := runtime.NumCPU()
-	// batchSize := len(noise) / numCPUs
-	// var wg sync.WaitGroup
-	// wg.Add(numCPUs)
-
-	// for i := 0; i < numCPUs; i++ {
-	// 	go func(i int) {
-	// 		defer wg.Done()
-	// 		start := i * batchSize
-	// 		end := start + batchSize - 1
-	// 		for j := start; j < end; j++ {
-	// 			x := j % w
-	// 			y := (j - x) / w
-	// 			noise[j] = Turbulence(
-	// 				float32(x), float32(y),
-	// 				frequency, lacunarity,
-	// 				gain, octaves,
-	// 			)
-
-	// 			if noise[j] < min {
-	// 				min = noise[j]
-	// 			} else if noise[j] > max {
-	// 				max = noise[j]
-	// 			}
-	// 		}
-	// 	}(i)
-	// }
-
-	i := 0
-	for y := 0; y < h; y++ {
+	if workers > h {
+		workers = h
+	}
+	if w == 0 || workers <= 0 {
+		return &Field{w, h, noise, 0, 0}
+	}
+
+	batchSize := (h + workers - 1) / workers
+	mins := make([]float32, workers)
+	maxs := make([]float32, workers)
+	var wg sync.WaitGroup
+
+	started := 0
+	for start := 0; start < h; start += batchSize {
+		end := start + batchSize
+		if end > h {
+			end = h
+		}
+
+		wg.Add(1)
+		go func(i, start, end int) {
+			defer wg.Done()
+			mins[i], maxs[i] = makeRows(noise, w, start, end, eval)
+		}(started, start, end)
+		started++
+	}
+	wg.Wait()
+
+	min, max := mins[0], maxs[0]
+	for i := 1; i < started; i++ {
+		if mins[i] < min {
+			min = mins[i]
+		}
+		if maxs[i] > max {
+			max = maxs[i]
+		}
+	}
+
+	return &Field{w, h, noise, min, max}
+}
+
+// makeRows fills rows [start, end) of the w wide noise slice and returns
+// the min and max of the values it generated
+func makeRows(noise []float32, w, start, end int, eval func(x, y float32) float32) (min, max float32) {
+	i := start * w
+	for y := start; y < end; y++ {
 		for x := 0; x < w; x++ {
-			// noise[i] = simplex.SNoise2(float32(x)/100.0, float32(y)/100.0)
-			if noiseType == TURBULENCE {
-				noise[i] = Turbulence(
-					float32(x), float32(y),
-					frequency, lacunarity,
-					gain, octaves,
-				)
-			} else {
-				noise[i] = Fbm2(
-					float32(x), float32(y),
-					frequency, lacunarity,
-					gain, octaves,
-				)
-			}
+			noise[i] = eval(float32(x), float32(y))
 
-			if noise[i] < min {
+			if i == start*w || noise[i] < min {
 				min = noise[i]
-			} else if noise[i] > max {
+			}
+			if i == start*w || noise[i] > max {
 				max = noise[i]
 			}
 			i++
 		}
 	}
 
-	// wg.Wait()
-	return noise
+	return min, max
 }
 
-var min = float32(math.MaxFloat32)
-var max = float32(-math.MaxFloat32)
+// Eval returns the value of the given noise type at x, y
+func Eval(noiseType Type, x, y float32, p Params) float32 {
+	sample := p.point(x, y).noise
+	if noiseType == CELLULAR {
+		sample = p.Worley.octave(x, y, p.TileW, p.TileH)
+	}
+
+	switch noiseType {
+	case TURBULENCE:
+		return turbulence(sample, p.Frequency, p.Lacunarity, p.Gain, p.Octaves)
+	case RIDGED:
+		return ridgedMulti(sample, p.Frequency, p.Lacunarity, p.Gain, p.Offset, p.H, p.Octaves)
+	case HYBRID:
+		return hybridMulti(sample, p.Frequency, p.Lacunarity, p.Offset, p.H, p.Octaves)
+	default:
+		return fbm(sample, p.Frequency, p.Lacunarity, p.Gain, p.Octaves)
+	}
+}
+
+// Normalize rescales the field values in place so they span [lo, hi].
+// A flat field is set to lo.
+func (f *Field) Normalize(lo, hi float32) {
+	scale := float32(0)
+	if f.Max > f.Min {
+		scale = (hi - lo) / (f.Max - f.Min)
+	}
 
-// Rescale scales the noise values by the given scale in place
-func (noise Noise) Rescale(scale float32) {
-	offset := min * scale
+	for i := range f.Values {
+		f.Values[i] = (f.Values[i]-f.Min)*scale + lo
+	}
 
-	for i := range noise {
-		noise[i] = noise[i]*scale - offset
+	f.Min = lo
+	f.Max = hi
+	if scale == 0 {
+		f.Max = lo
 	}
 }
 
-// CalcScale calculates and returns the scale value using the give value.
-// `scale = value / (max-min)`
-func CalcScale(value float32) float32 {
-	return value / (max - min)
+// At returns the noise value at x, y
+func (f *Field) At(x, y int) float32 {
+	return f.Values[y*f.W+x]
 }
 
 // Fbm2 generates fractal brownian motion noise
 func Fbm2(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
+	return fbm(planePoint(x, y).noise, frequency, lacunarity, gain, octaves)
+}
+
+// Turbulence generates turbulence fractal noise
+func Turbulence(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
+	return turbulence(planePoint(x, y).noise, frequency, lacunarity, gain, octaves)
+}
+
+// RidgedMulti generates Musgrave's ridged multifractal noise. Each octave
+// is weighted by the previous one scaled by gain, so ridges stay sharp
+// while valleys are smoothed out. Musgrave suggests an offset of 1, H of 1
+// and gain of 2.
+func RidgedMulti(x, y, frequency, lacunarity, gain, offset, H float32, octaves int) float32 {
+	return ridgedMulti(planePoint(x, y).noise, frequency, lacunarity, gain, offset, H, octaves)
+}
+
+// HybridMulti generates Musgrave's hybrid multifractal noise. Smooth areas
+// stay smooth while rough areas get rougher with each octave. Musgrave
+// suggests an offset of 0.7 and H of 0.25.
+func HybridMulti(x, y, frequency, lacunarity, offset, H float32, octaves int) float32 {
+	return hybridMulti(planePoint(x, y).noise, frequency, lacunarity, offset, H, octaves)
+}
+
+// octave samples one location of a noise source at a frequency, in the
+// range of simplex.SNoise2. The fractal functions sum octaves of it.
+type octave func(frequency float32) float32
+
+func fbm(sample octave, frequency, lacunarity, gain float32, octaves int) float32 {
 	var sum float32
 	amplitude := float32(1.0)
 
 	for i := 0; i < octaves; i++ {
-		sum += simplex.SNoise2(x*frequency, y*frequency) * amplitude
+		sum += sample(frequency) * amplitude
 		frequency *= lacunarity
 		amplitude *= gain
 	}
@@ -114,13 +242,12 @@ func Fbm2(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
 	return sum
 }
 
-// Turbulence generates turbulence fractal noise
-func Turbulence(x, y, frequency, lacunarity, gain float32, octaves int) float32 {
+func turbulence(sample octave, frequency, lacunarity, gain float32, octaves int) float32 {
 	var sum float32
 	amplitude := float32(1.0)
 
 	for i := 0; i < octaves; i++ {
-		n := simplex.SNoise2(x*frequency, y*frequency) * amplitude
+		n := sample(frequency) * amplitude
 		if n < 0 {
 			n = -1.0 * n
 		}
@@ -131,3 +258,64 @@ func Turbulence(x, y, frequency, lacunarity, gain float32, octaves int) float32
 
 	return sum
 }
+
+func ridgedMulti(sample octave, frequency, lacunarity, gain, offset, H float32, octaves int) float32 {
+	// the spectral weight of the next octave is lacunarity^(-H*i)
+	exponent := float32(math.Pow(float64(lacunarity), float64(-H)))
+	amplitude := float32(1.0)
+
+	var sum float32
+	weight := float32(1.0)
+
+	for i := 0; i < octaves; i++ {
+		signal := unitScale * sample(frequency)
+		if signal < 0 {
+			signal = -signal
+		}
+		// invert and square the signal to get sharp ridges
+		signal = offset - signal
+		signal *= signal
+		signal *= weight
+
+		sum += signal * amplitude
+
+		weight = signal * gain
+		if weight > 1 {
+			weight = 1
+		} else if weight < 0 {
+			weight = 0
+		}
+
+		frequency *= lacunarity
+		amplitude *= exponent
+	}
+
+	return sum
+}
+
+func hybridMulti(sample octave, frequency, lacunarity, offset, H float32, octaves int) float32 {
+	if octaves < 1 {
+		return 0
+	}
+
+	exponent := float32(math.Pow(float64(lacunarity), float64(-H)))
+	amplitude := float32(1.0)
+
+	sum := (unitScale*sample(frequency) + offset) * amplitude
+	weight := sum
+
+	for i := 1; i < octaves; i++ {
+		frequency *= lacunarity
+		amplitude *= exponent
+
+		if weight > 1 {
+			weight = 1
+		}
+
+		signal := (unitScale*sample(frequency) + offset) * amplitude
+		sum += weight * signal
+		weight *= signal
+	}
+
+	return sum
+}
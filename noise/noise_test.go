@@ -0,0 +1,111 @@
+package noise
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/dikaeinstein/games-with-go/simplex"
+)
+
+func TestMakeFieldMatchesSerial(t *testing.T) {
+	tests := []struct {
+		name      string
+		noiseType Type
+		p         Params
+	}{
+		{"fbm", FBM, DefaultParams(FBM, 0.01, 2, 0.5, 4)},
+		{"turbulence", TURBULENCE, DefaultParams(TURBULENCE, 0.02, 3, 0.2, 3)},
+		{"ridged", RIDGED, DefaultParams(RIDGED, 0.005, 2, 2, 5)},
+		{"hybrid", HYBRID, DefaultParams(HYBRID, 0.005, 2, 0.5, 5)},
+		{"cellular", CELLULAR, DefaultParams(CELLULAR, 0.02, 2, 0.5, 3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eval := fieldEval(tt.noiseType, tt.p)
+			serial := makeField(123, 77, 1, eval)
+			// more workers than CPUs so the rows are split even on one CPU
+			parallel := makeField(123, 77, runtime.NumCPU()+7, eval)
+
+			if math.Float32bits(parallel.Min) != math.Float32bits(serial.Min) ||
+				math.Float32bits(parallel.Max) != math.Float32bits(serial.Max) {
+				t.Errorf("min, max = %v, %v, want %v, %v",
+					parallel.Min, parallel.Max, serial.Min, serial.Max)
+			}
+			for i := range serial.Values {
+				if math.Float32bits(parallel.Values[i]) != math.Float32bits(serial.Values[i]) {
+					t.Fatalf("value %d = %v, want %v", i, parallel.Values[i], serial.Values[i])
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMakeNoise(b *testing.B) {
+	eval := fieldEval(FBM, DefaultParams(FBM, 0.01, 2, 0.5, 6))
+
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			makeField(800, 600, 1, eval)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			makeField(800, 600, runtime.NumCPU(), eval)
+		}
+	})
+}
+
+// TestUntiledSumsSNoise2 checks that untiled fbm and turbulence add up
+// simplex.SNoise2 octaves unscaled, as they always have
+func TestUntiledSumsSNoise2(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y := r.Float32()*2000-1000, r.Float32()*2000-1000
+
+		var fbmWant, turbulenceWant float32
+		frequency, amplitude := float32(0.01), float32(1)
+		for o := 0; o < 5; o++ {
+			n := simplex.SNoise2(x*frequency, y*frequency) * amplitude
+			fbmWant += n
+			if n < 0 {
+				n = -n
+			}
+			turbulenceWant += n
+			frequency *= 2
+			amplitude *= 0.5
+		}
+
+		if got := Fbm2(x, y, 0.01, 2, 0.5, 5); math.Float32bits(got) != math.Float32bits(fbmWant) {
+			t.Fatalf("Fbm2(%v, %v) = %v, want %v", x, y, got, fbmWant)
+		}
+		if got := Turbulence(x, y, 0.01, 2, 0.5, 5); math.Float32bits(got) != math.Float32bits(turbulenceWant) {
+			t.Fatalf("Turbulence(%v, %v) = %v, want %v", x, y, got, turbulenceWant)
+		}
+	}
+}
+
+// TestValueRange checks that the warp signal of every noise type maps to
+// about [-1,1], so Strength means the same displacement for all of them
+func TestValueRange(t *testing.T) {
+	names := []string{"fbm", "turbulence", "ridged", "hybrid", "cellular"}
+	for noiseType := FBM; noiseType <= CELLULAR; noiseType++ {
+		for _, octaves := range []int{1, 3, 6} {
+			gain := float32(0.5)
+			if noiseType == RIDGED {
+				gain = 2
+			}
+			p := DefaultParams(noiseType, 0.01, 2, gain, octaves)
+			f := makeField(200, 200, 1, fieldEval(noiseType, p))
+
+			center, halfWidth := valueRange(noiseType, p)
+			lo, hi := (f.Min-center)/halfWidth, (f.Max-center)/halfWidth
+			if lo < -1.05 || hi > 1.05 || hi-lo < 0.5 {
+				t.Errorf("%s with %d octaves maps to [%v, %v], want about [-1,1]",
+					names[noiseType], octaves, lo, hi)
+			}
+		}
+	}
+}
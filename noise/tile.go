@@ -0,0 +1,71 @@
+package noise
+
+import (
+	"math"
+
+	"github.com/dikaeinstein/games-with-go/simplex"
+)
+
+// unitScale is the final scale factor of the reference 2D simplex noise,
+// which simplex.SNoise2 leaves out. The 3D and 4D functions keep theirs.
+const unitScale = 40
+
+// point is a location in noise space. Untiled noise samples the plane,
+// noise that wraps around one axis samples a cylinder in 3D and noise
+// that wraps around both axes samples a torus in 4D, made of a circle
+// for each axis.
+type point struct {
+	x, y, z, w float32
+	dims       int
+	gen        *simplex.Generator
+}
+
+func planePoint(x, y float32) point {
+	return point{x: x, y: y, dims: 2, gen: simplex.Default()}
+}
+
+// circle maps v onto a circle whose circumference is period, so that
+// distances along the circle match distances along the axis
+func circle(v float32, period int) (c, s float32) {
+	r := float64(period) / (2 * math.Pi)
+	a := float64(v) / r
+	return float32(r * math.Cos(a)), float32(r * math.Sin(a))
+}
+
+// point returns the noise space location of x, y, wrapped according to
+// TileW and TileH
+func (p Params) point(x, y float32) point {
+	pt := planePoint(x, y)
+	if p.Generator != nil {
+		pt.gen = p.Generator
+	}
+
+	switch {
+	case p.TileW > 0 && p.TileH > 0:
+		pt.x, pt.y = circle(x, p.TileW)
+		pt.z, pt.w = circle(y, p.TileH)
+		pt.dims = 4
+	case p.TileW > 0:
+		pt.x, pt.y = circle(x, p.TileW)
+		pt.z = y
+		pt.dims = 3
+	case p.TileH > 0:
+		pt.y, pt.z = circle(y, p.TileH)
+		pt.dims = 3
+	}
+	return pt
+}
+
+// noise returns the noise at the point scaled by frequency, in the range
+// of simplex.SNoise2. Plane points return it unchanged, the 3D and 4D
+// samples of tiled noise are scaled down to match it.
+func (p point) noise(frequency float32) float32 {
+	switch p.dims {
+	case 4:
+		return p.gen.Noise4(p.x*frequency, p.y*frequency, p.z*frequency, p.w*frequency) / unitScale
+	case 3:
+		return p.gen.Noise3(p.x*frequency, p.y*frequency, p.z*frequency) / unitScale
+	default:
+		return p.gen.Noise2(p.x*frequency, p.y*frequency)
+	}
+}
@@ -0,0 +1,100 @@
+package noise
+
+import (
+	"math"
+	"runtime"
+)
+
+// warpOffsets are added to the coordinates of the two noise lookups of
+// each warp layer so the x and y displacements are not correlated. The
+// first four are the ones used by Inigo Quilez, after that they repeat.
+var warpOffsets = [][2]float32{
+	{0, 0}, {5.2, 1.3},
+	{1.7, 9.2}, {8.3, 2.8},
+	{4.1, 7.6}, {6.9, 0.4},
+}
+
+// Warp describes domain warped noise, where the coordinates of a noise
+// lookup are first displaced by another noise:
+//
+//	Iterations 1: f(p + k*w(p))
+//	Iterations 2: f(p + k*w(p + k*w(p)))
+//
+// with f the Type noise, w the WarpType noise mapped to about [-1,1] and
+// k the Strength.
+type Warp struct {
+	// Type and Params are the noise sampled at the warped coordinates
+	Type   Type
+	Params Params
+	// WarpType and WarpParams are the noise that displaces the coordinates
+	WarpType   Type
+	WarpParams Params
+	// Strength is the largest displacement, in field units, whatever the
+	// WarpType
+	Strength float32
+	// Iterations is the number of nested warps
+	Iterations int
+}
+
+// Eval returns the domain warped noise at x, y
+func (wp Warp) Eval(x, y float32) float32 {
+	// the offsets live in noise space, scale them back to field units
+	scale := float32(1)
+	if wp.WarpParams.Frequency != 0 {
+		scale = 1 / wp.WarpParams.Frequency
+	}
+
+	center, halfWidth := valueRange(wp.WarpType, wp.WarpParams)
+	if halfWidth == 0 {
+		return Eval(wp.Type, x, y, wp.Params)
+	}
+
+	var qx, qy float32
+	for i := 0; i < wp.Iterations; i++ {
+		px := x + wp.Strength*qx
+		py := y + wp.Strength*qy
+
+		a := warpOffsets[(2*i)%len(warpOffsets)]
+		b := warpOffsets[(2*i+1)%len(warpOffsets)]
+		qx = (Eval(wp.WarpType, px+a[0]*scale, py+a[1]*scale, wp.WarpParams) - center) / halfWidth
+		qy = (Eval(wp.WarpType, px+b[0]*scale, py+b[1]*scale, wp.WarpParams) - center) / halfWidth
+	}
+
+	return Eval(wp.Type, x+wp.Strength*qx, y+wp.Strength*qy, wp.Params)
+}
+
+// valueRange estimates the center and half width of the values Eval
+// returns for noiseType with p, from the summed amplitude of its octaves.
+// The fbm types sum octaves in the range of simplex.SNoise2 while the
+// multifractals sum octaves in [-1,1] shifted by the offset.
+func valueRange(noiseType Type, p Params) (center, halfWidth float32) {
+	step := p.Gain
+	if noiseType == RIDGED || noiseType == HYBRID {
+		step = float32(math.Pow(float64(p.Lacunarity), float64(-p.H)))
+	}
+	var total float32
+	amplitude := float32(1)
+	for i := 0; i < p.Octaves; i++ {
+		total += amplitude
+		amplitude *= step
+	}
+
+	switch noiseType {
+	case TURBULENCE:
+		// the absolute octaves sum to [0, total]
+		return total / unitScale / 2, total / unitScale / 2
+	case RIDGED:
+		// each octave is at most offset squared
+		peak := p.Offset * p.Offset * total
+		return peak / 2, peak / 2
+	case HYBRID:
+		return p.Offset * total, total
+	default:
+		return 0, total / unitScale
+	}
+}
+
+// MakeField creates a w by h Field of domain warped noise
+func (wp Warp) MakeField(w, h int) *Field {
+	return makeField(w, h, runtime.NumCPU(), wp.Eval)
+}
@@ -0,0 +1,158 @@
+package noise
+
+import "math"
+
+// Metric is the distance function used by Worley noise
+type Metric uint
+
+const (
+	// EUCLIDEAN is the straight line distance, giving round cells
+	EUCLIDEAN Metric = iota
+	// MANHATTAN is the sum of the axis distances, giving diamond cells
+	MANHATTAN
+	// CHEBYSHEV is the largest axis distance, giving square cells
+	CHEBYSHEV
+)
+
+// Feature is the value Worley noise returns for a point
+type Feature uint
+
+const (
+	// F1 is the distance to the closest feature point
+	F1 Feature = iota
+	// F2 is the distance to the second closest feature point
+	F2
+	// F2MINUSF1 is F2 - F1, which is zero on the cell borders
+	F2MINUSF1
+)
+
+// Worley configures cellular noise. Space is divided in unit cells each
+// holding one feature point, jittered by a hash of the cell and Seed.
+type Worley struct {
+	Seed    int64
+	Metric  Metric
+	Feature Feature
+}
+
+// Cellular returns the Worley noise at x, y, scaled by frequency
+func (c Worley) Cellular(x, y, frequency float32) float32 {
+	return c.eval(x*frequency, y*frequency, 0, 0)
+}
+
+// octave returns the sampler of the Worley noise at x, y, wrapped
+// according to tileW and tileH and scaled to the range of the simplex
+// octaves
+func (c Worley) octave(x, y float32, tileW, tileH int) octave {
+	return func(frequency float32) float32 {
+		return c.unitNoise(x, y, frequency, tileW, tileH) / unitScale
+	}
+}
+
+// unitNoise returns the Worley noise at x, y scaled by frequency, mapped
+// to roughly [-1,1]. When tiling, the cells are stretched so a whole
+// number of them fits in each tile, and the cell coordinates wrap.
+func (c Worley) unitNoise(x, y, frequency float32, tileW, tileH int) float32 {
+	u, v := x*frequency, y*frequency
+	var cellsX, cellsY int
+	if tileW > 0 {
+		cellsX = tileCells(tileW, frequency)
+		u = x * float32(cellsX) / float32(tileW)
+	}
+	if tileH > 0 {
+		cellsY = tileCells(tileH, frequency)
+		v = y * float32(cellsY) / float32(tileH)
+	}
+
+	return 2*c.eval(u, v, cellsX, cellsY) - 1
+}
+
+// tileCells returns how many whole cells fit in a tile at frequency
+func tileCells(tile int, frequency float32) int {
+	n := int(math.Round(float64(float32(tile) * frequency)))
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// eval returns the Worley feature at u, v in cell space. Cell indices
+// wrap around cellsX and cellsY when they are not zero.
+func (c Worley) eval(u, v float32, cellsX, cellsY int) float32 {
+	ci := int(math.Floor(float64(u)))
+	cj := int(math.Floor(float64(v)))
+
+	f1 := float32(math.MaxFloat32)
+	f2 := float32(math.MaxFloat32)
+
+	// the closest feature points are in the 3x3 neighbourhood of the cell
+	for j := cj - 1; j <= cj+1; j++ {
+		for i := ci - 1; i <= ci+1; i++ {
+			fx, fy := c.featurePoint(wrap(i, cellsX), wrap(j, cellsY))
+			d := c.distance(float32(i)+fx-u, float32(j)+fy-v)
+			if d < f1 {
+				f2 = f1
+				f1 = d
+			} else if d < f2 {
+				f2 = d
+			}
+		}
+	}
+
+	switch c.Feature {
+	case F2:
+		return f2
+	case F2MINUSF1:
+		return f2 - f1
+	default:
+		return f1
+	}
+}
+
+// wrap returns i modulo n, or i when n is zero
+func wrap(i, n int) int {
+	if n == 0 {
+		return i
+	}
+	i %= n
+	if i < 0 {
+		i += n
+	}
+	return i
+}
+
+func (c Worley) distance(dx, dy float32) float32 {
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+
+	switch c.Metric {
+	case MANHATTAN:
+		return dx + dy
+	case CHEBYSHEV:
+		if dx > dy {
+			return dx
+		}
+		return dy
+	default:
+		return float32(math.Sqrt(float64(dx*dx + dy*dy)))
+	}
+}
+
+// featurePoint returns the position of the feature point of cell i, j
+// relative to the cell origin
+func (c Worley) featurePoint(i, j int) (x, y float32) {
+	h := uint32(i)*0x8da6b343 ^ uint32(j)*0xd8163841 ^
+		uint32(c.Seed)*0xcb1ab31f ^ uint32(c.Seed>>32)*0x165667b1
+
+	// final avalanche so nearby cells get unrelated points
+	h ^= h >> 16
+	h *= 0x7feb352d
+	h ^= h >> 15
+	h *= 0x846ca68b
+	h ^= h >> 16
+
+	return float32(h&0xffff) / 65536, float32(h>>16) / 65536
+}
@@ -1,11 +1,16 @@
 package game
 
+import (
+	"github.com/dikaeinstein/games-with-go/geometry"
+	"github.com/dikaeinstein/games-with-go/vector"
+)
+
 // Ball represents the ball in the pong game
 type Ball struct {
 	Pos
-	radius               float32
-	xVelocity, yVelocity float32
-	color                Color
+	radius   float32
+	velocity vector.Vec2
+	color    Color
 }
 
 // NewBall creates an instance of a Ball
@@ -13,7 +18,7 @@ func NewBall(pos Pos, radius, xVelocity, yVelocity float32, color Color) *Ball {
 	return &Ball{
 		pos,
 		radius,
-		xVelocity, yVelocity,
+		vector.Vec2{X: xVelocity, Y: yVelocity},
 		color,
 	}
 }
@@ -30,14 +35,27 @@ func (b *Ball) Draw(pixels []byte) {
 	}
 }
 
-// Update updates the position of the ball based on collision with paddles
+// maxBounces limits how many times the ball can bounce in one update
+const maxBounces = 4
+
+// Update moves the ball by its velocity, bouncing off the walls and the
+// paddles. The movement is swept, so a long frame cannot make the ball go
+// through a paddle. After maxBounces bounces the rest of the frame time
+// is dropped and the ball stays at the last contact, since moving it
+// further unswept could take it through an obstacle.
 func (b *Ball) Update(leftPaddle, rightPaddle *Paddle, elapsedTime float32) {
-	b.X += b.xVelocity * elapsedTime
-	b.Y += b.yVelocity * elapsedTime
+	remaining := elapsedTime
+	for i := 0; i < maxBounces && remaining > 0; i++ {
+		move := b.velocity.Scale(remaining)
+		hit, ok := b.sweep(move, leftPaddle, rightPaddle)
+		if !ok {
+			b.Pos = b.Add(move)
+			break
+		}
 
-	if b.Y-b.radius < 0 || b.Y+b.radius > float32(winHeight) {
-		// invert the y component of the ball velocity
-		b.yVelocity = -b.yVelocity
+		b.Pos = hit.Point
+		remaining -= remaining * hit.T
+		b.velocity = b.velocity.Reflect(hit.Normal)
 	}
 
 	if b.X < 0 {
@@ -49,20 +67,36 @@ func (b *Ball) Update(leftPaddle, rightPaddle *Paddle, elapsedTime float32) {
 		b.Pos = GetCenter()
 		state = StateStart
 	}
+}
 
-	if b.X-b.radius < leftPaddle.X+leftPaddle.w/2 {
-		if b.Y > leftPaddle.Y-leftPaddle.h/2 && b.Y < leftPaddle.Y+leftPaddle.h/2 {
-			b.xVelocity = -b.xVelocity
-			// minimum translation vector after collision
-			b.X = leftPaddle.X + leftPaddle.w/2 + b.radius
-		}
+// sweep returns the first wall or paddle the ball hits when moving by
+// move. Obstacles the ball is already moving away from are ignored.
+func (b *Ball) sweep(move vector.Vec2, leftPaddle, rightPaddle *Paddle) (geometry.Hit, bool) {
+	w, h := float32(winWidth), float32(winHeight)
+	walls := []geometry.Segment{
+		{A: vector.Vec2{X: 0, Y: 0}, B: vector.Vec2{X: w, Y: 0}},
+		{A: vector.Vec2{X: 0, Y: h}, B: vector.Vec2{X: w, Y: h}},
 	}
 
-	if b.X+b.radius > rightPaddle.X-rightPaddle.w/2 {
-		if b.Y > rightPaddle.Y-rightPaddle.h/2 && b.Y < rightPaddle.Y+rightPaddle.h/2 {
-			b.xVelocity = -b.xVelocity
-			// minimum translation vector
-			b.X = rightPaddle.X - rightPaddle.w/2 - b.radius
+	var best geometry.Hit
+	found := false
+	keep := func(hit geometry.Hit, ok bool) {
+		if ok && move.Dot(hit.Normal) < 0 && (!found || hit.T < best.T) {
+			best, found = hit, true
 		}
 	}
+
+	c := b.Circle()
+	for _, wall := range walls {
+		keep(geometry.SweepCircleSegment(c, move, wall))
+	}
+	for _, p := range []*Paddle{leftPaddle, rightPaddle} {
+		keep(geometry.SweepCircleAABB(c, move, p.Rect()))
+	}
+	return best, found
+}
+
+// Circle returns the shape of the ball
+func (b *Ball) Circle() geometry.Circle {
+	return geometry.Circle{Center: b.Pos, Radius: b.radius}
 }
@@ -0,0 +1,40 @@
+package game
+
+import "testing"
+
+func TestBallUpdateDoesNotTunnel(t *testing.T) {
+	Init(800, 600)
+	InitState()
+	left := NewPaddle(Pos{X: 100, Y: 300}, 20, 100, 300, 0, Color{})
+	right := NewPaddle(Pos{X: 700, Y: 300}, 20, 100, 300, 0, Color{})
+
+	tests := []struct {
+		name          string
+		elapsedTime   float32
+		wantX, wantVX float32
+	}{
+		// the ball reaches the left paddle after 180 of its 500 units and
+		// bounces back for the other 320
+		{"long frame", 0.5, 440, 1000},
+		// after bouncing off the left paddle the ball travels 560 to the
+		// right paddle, then bounces back for the last 10
+		{"two bounces", 0.75, 670, -1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ball := NewBall(Pos{X: 300, Y: 300}, 10, -1000, 0, Color{})
+			ball.Update(left, right, tt.elapsedTime)
+
+			if ball.X != tt.wantX || ball.Y != 300 {
+				t.Errorf("ball at %v, %v, want %v, 300", ball.X, ball.Y, tt.wantX)
+			}
+			if ball.velocity.X != tt.wantVX || ball.velocity.Y != 0 {
+				t.Errorf("velocity %v, want %v, 0", ball.velocity, tt.wantVX)
+			}
+			if left.score != 0 || right.score != 0 {
+				t.Errorf("scores %d, %d, want no point scored", left.score, right.score)
+			}
+		})
+	}
+}
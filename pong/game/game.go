@@ -1,6 +1,7 @@
 package game
 
 import (
+	"github.com/dikaeinstein/games-with-go/vector"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
@@ -10,9 +11,7 @@ type Color struct {
 }
 
 // Pos represents an object position in a 2D space
-type Pos struct {
-	X, Y float32
-}
+type Pos = vector.Vec2
 
 // Object is an interface to game objects like paddle, ball, score etc
 type Object interface {
@@ -77,7 +76,7 @@ func ClearPixels(pixels []byte) {
 
 // GetCenter returns the center position of the window
 func GetCenter() Pos {
-	return Pos{float32(winWidth) / 2, float32(winHeight) / 2}
+	return Pos{X: float32(winWidth) / 2, Y: float32(winHeight) / 2}
 }
 
 // Lerp is the linear interpolation between point a and b
@@ -3,6 +3,7 @@ package game
 import (
 	"math"
 
+	"github.com/dikaeinstein/games-with-go/geometry"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
@@ -60,6 +61,11 @@ func (p *Paddle) AIUpdate(ball *Ball, elapsedTime float32) {
 	p.Y = ball.Y
 }
 
+// Rect returns the box the paddle covers
+func (p *Paddle) Rect() geometry.AABB {
+	return geometry.RectAABB(p.Pos, p.w, p.h)
+}
+
 // GetScore returns the current score of this player/paddle
 func (p *Paddle) GetScore() Score {
 	return p.score
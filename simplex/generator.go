@@ -0,0 +1,54 @@
+package simplex
+
+import "math/rand"
+
+// Generator generates simplex noise from its own permutation table.
+// Generators built from the same seed produce the same noise.
+type Generator struct {
+	perm [256]uint8
+}
+
+// NewGenerator creates a Generator whose permutation table is a
+// deterministic shuffle of 0-255 seeded with the given seed
+func NewGenerator(seed int64) *Generator {
+	g := &Generator{}
+	for i := range g.perm {
+		g.perm[i] = uint8(i)
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(g.perm), func(i, j int) {
+		g.perm[i], g.perm[j] = g.perm[j], g.perm[i]
+	})
+
+	return g
+}
+
+// defaultGenerator uses the static permutation table of the reference
+// implementation and backs the package level noise functions
+var defaultGenerator = &Generator{perm}
+
+// Default returns the generator used by the package level noise functions
+func Default() *Generator {
+	return defaultGenerator
+}
+
+// SNoise1 1D simplex noise
+func SNoise1(x float32) float32 {
+	return defaultGenerator.Noise1(x)
+}
+
+// SNoise2 2D simplex noise
+func SNoise2(x, y float32) float32 {
+	return defaultGenerator.Noise2(x, y)
+}
+
+// SNoise3 3D simplex noise
+func SNoise3(x, y, z float32) float32 {
+	return defaultGenerator.Noise3(x, y, z)
+}
+
+// SNoise4 4D simplex noise
+func SNoise4(x, y, z, w float32) float32 {
+	return defaultGenerator.Noise4(x, y, z, w)
+}
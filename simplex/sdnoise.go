@@ -0,0 +1,218 @@
+/* Simplex noise with true analytic derivatives, ported from Stefan
+ * Gustavson's sdnoise1234.c:
+ * https://github.com/stegu/perlin-noise/blob/master/src/sdnoise1234.c
+ *
+ * The derivative variants share the permutation table, gradients and
+ * scaling of the plain noise functions in simplex.go, so the value they
+ * return is the same as the one returned by Noise2 and Noise3.
+ *
+ * The analytic derivative of each corner contribution
+ * n = t^4 * (g.d), with t = r - |d|^2, is
+ * dn/dd = t^4 * g - 8 * t^3 * (g.d) * d
+ *
+ * Like in simplex.go, products that feed a sum are wrapped in float32
+ * conversions so they are not fused into multiply-adds, which would make
+ * the values differ from Noise2 and Noise3 on some architectures.
+ */
+
+package simplex
+
+// SNoise2Deriv 2D simplex noise with its analytic partial derivatives
+func SNoise2Deriv(x, y float32) (n, dx, dy float32) {
+	return defaultGenerator.Noise2Deriv(x, y)
+}
+
+// SNoise3Deriv 3D simplex noise with its analytic partial derivatives
+func SNoise3Deriv(x, y, z float32) (n, dx, dy, dz float32) {
+	return defaultGenerator.Noise3Deriv(x, y, z)
+}
+
+// grad2Vec returns the gradient vector picked by grad2 for the given hash
+func grad2Vec(hash uint8) (gx, gy float32) {
+	h := hash & 7
+	// u and v select the components grad2 adds together
+	var u, v float32 = 1, 2
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+
+	if h < 4 {
+		return u, v
+	}
+	return v, u
+}
+
+// grad3Vec returns the gradient vector picked by grad3 for the given hash
+func grad3Vec(hash uint8) (gx, gy, gz float32) {
+	h := hash & 15
+	// u and v select the components grad3 adds together
+	var u, v float32 = 1, 1
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+
+	switch {
+	case h < 4:
+		return u, v, 0
+	case h < 8:
+		return u, 0, v
+	case h == 12 || h == 14:
+		return v, u, 0
+	default:
+		return 0, u, v
+	}
+}
+
+// corner2 returns the contribution of a 2D simplex corner at offset (x,y)
+// and its derivative
+func corner2(hash uint8, x, y float32) (n, dx, dy float32) {
+	t := 0.5 - float32(x*x) - float32(y*y)
+	if t < 0.0 {
+		return 0, 0, 0
+	}
+
+	gx, gy := grad2Vec(hash)
+	gdot := float32(gx*x) + float32(gy*y)
+	t2 := t * t
+	t4 := t2 * t2
+	t3 := t2 * t * gdot
+
+	n = t4 * gdot
+	dx = float32(t4*gx) - float32(8*t3*x)
+	dy = float32(t4*gy) - float32(8*t3*y)
+	return n, dx, dy
+}
+
+// corner3 returns the contribution of a 3D simplex corner at offset
+// (x,y,z) and its derivative
+func corner3(hash uint8, x, y, z float32) (n, dx, dy, dz float32) {
+	t := 0.6 - float32(x*x) - float32(y*y) - float32(z*z)
+	if t < 0.0 {
+		return 0, 0, 0, 0
+	}
+
+	gx, gy, gz := grad3Vec(hash)
+	gdot := float32(gx*x) + float32(gy*y) + float32(gz*z)
+	t2 := t * t
+	t4 := t2 * t2
+	t3 := t2 * t * gdot
+
+	n = t4 * gdot
+	dx = float32(t4*gx) - float32(8*t3*x)
+	dy = float32(t4*gy) - float32(8*t3*y)
+	dz = float32(t4*gz) - float32(8*t3*z)
+	return n, dx, dy, dz
+}
+
+// Noise2Deriv returns the 2D simplex noise at the given point together
+// with its partial derivatives along x and y
+func (g *Generator) Noise2Deriv(x, y float32) (n, dx, dy float32) {
+	perm := &g.perm
+
+	const F2 float32 = 0.366025403 // F2 = 0.5*(sqrt(3.0)-1.0)
+	const G2 float32 = 0.211324865 // G2 = (3.0-Math.sqrt(3.0))/6.0
+
+	// Skew the input space to determine which simplex cell we're in
+	s := float32((x + y) * F2)
+	i := fastFloor(x + s)
+	j := fastFloor(y + s)
+
+	t := float32(float32(i+j) * G2)
+	x0 := x - (float32(i) - t) // The x,y distances from the cell origin
+	y0 := y - (float32(j) - t)
+
+	// Offsets for second (middle) corner of simplex in (i,j) coords
+	var i1, j1 uint8 = 0, 1
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	}
+
+	x1 := x0 - float32(i1) + G2 // Offsets for middle corner in (x,y) unskewed coords
+	y1 := y0 - float32(j1) + G2
+	x2 := x0 - 1.0 + 2.0*G2 // Offsets for last corner in (x,y) unskewed coords
+	y2 := y0 - 1.0 + 2.0*G2
+
+	// Wrap the integer indices at 256, to avoid indexing perm[] out of bounds
+	ii := uint8(i)
+	jj := uint8(j)
+
+	n0, dx0, dy0 := corner2(perm[ii+perm[jj]], x0, y0)
+	n1, dx1, dy1 := corner2(perm[ii+i1+perm[jj+j1]], x1, y1)
+	n2, dx2, dy2 := corner2(perm[ii+1+perm[jj+1]], x2, y2)
+
+	return n0 + n1 + n2, dx0 + dx1 + dx2, dy0 + dy1 + dy2
+}
+
+// Noise3Deriv returns the 3D simplex noise at the given point together
+// with its partial derivatives along x, y and z
+func (g *Generator) Noise3Deriv(x, y, z float32) (n, dx, dy, dz float32) {
+	perm := &g.perm
+
+	// Skewing factors in double precision, see Noise3
+	const F3 = 0.333333333
+	const G3 = 0.166666667
+
+	// Skew the input space to determine which simplex cell we're in
+	s := float32(float64(x+y+z) * F3)
+	i := fastFloor(x + s)
+	j := fastFloor(y + s)
+	k := fastFloor(z + s)
+
+	t := float32(float64(float32(i+j+k)) * G3)
+	x0 := x - (float32(i) - t) // The x,y,z distances from the cell origin
+	y0 := y - (float32(j) - t)
+	z0 := z - (float32(k) - t)
+
+	var i1, j1, k1 uint8 // Offsets for second corner of simplex in (i,j,k) coords
+	var i2, j2, k2 uint8 // Offsets for third corner of simplex in (i,j,k) coords
+
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0 // X Y Z order
+		} else if x0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1 // X Z Y order
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1 // Z X Y order
+		}
+	} else { // x0<y0
+		if y0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1 // Z Y X order
+		} else if x0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1 // Y Z X order
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0 // Y X Z order
+		}
+	}
+
+	x1 := float32(float64(x0-float32(i1)) + G3) // Offsets for second corner in (x,y,z) coords
+	y1 := float32(float64(y0-float32(j1)) + G3)
+	z1 := float32(float64(z0-float32(k1)) + G3)
+	x2 := float32(float64(x0-float32(i2)) + 2.0*G3) // Offsets for third corner in (x,y,z) coords
+	y2 := float32(float64(y0-float32(j2)) + 2.0*G3)
+	z2 := float32(float64(z0-float32(k2)) + 2.0*G3)
+	x3 := float32(float64(x0-1.0) + 3.0*G3) // Offsets for last corner in (x,y,z) coords
+	y3 := float32(float64(y0-1.0) + 3.0*G3)
+	z3 := float32(float64(z0-1.0) + 3.0*G3)
+
+	// Wrap the integer indices at 256, to avoid indexing perm[] out of bounds
+	ii := uint8(i)
+	jj := uint8(j)
+	kk := uint8(k)
+
+	n0, dx0, dy0, dz0 := corner3(perm[ii+perm[jj+perm[kk]]], x0, y0, z0)
+	n1, dx1, dy1, dz1 := corner3(perm[ii+i1+perm[jj+j1+perm[kk+k1]]], x1, y1, z1)
+	n2, dx2, dy2, dz2 := corner3(perm[ii+i2+perm[jj+j2+perm[kk+k2]]], x2, y2, z2)
+	n3, dx3, dy3, dz3 := corner3(perm[ii+1+perm[jj+1+perm[kk+1]]], x3, y3, z3)
+
+	// Scale the noise and its derivatives like Noise3 does
+	return 32.0 * (n0 + n1 + n2 + n3),
+		32.0 * (dx0 + dx1 + dx2 + dx3),
+		32.0 * (dy0 + dy1 + dy2 + dy3),
+		32.0 * (dz0 + dz1 + dz2 + dz3)
+}
@@ -31,6 +31,11 @@
 // Package simplex implements simplex noise
 package simplex
 
+// Go may fuse a multiplication and an addition into one instruction that
+// rounds once, as it does on arm64. Products that feed a sum are wrapped
+// in explicit float32 conversions, which round them and prevent fusing,
+// so the noise matches the reference on every architecture.
+
 func fastFloor(x float32) int {
 	if float32(int(x)) <= x {
 		return int(x)
@@ -46,6 +51,8 @@ func fastFloor(x float32) int {
  * so it's easiest to just keep it as static explicit data.
  * This also removes the need for any initialisation of this class.
  *
+ * It is the table used by the default generator; seeded generators
+ * shuffle their own copy, see NewGenerator.
  */
 var perm = [256]uint8{151, 160, 137, 91, 90, 15,
 	131, 13, 201, 95, 96, 53, 194, 233, 7, 225, 140, 36, 103, 30, 69, 142, 8, 99, 37, 240, 21, 10, 23,
@@ -61,8 +68,44 @@ var perm = [256]uint8{151, 160, 137, 91, 90, 15,
 	49, 192, 214, 31, 181, 199, 106, 157, 184, 84, 204, 176, 115, 121, 50, 45, 127, 4, 150, 254,
 	138, 236, 205, 93, 222, 114, 67, 29, 24, 72, 243, 141, 128, 195, 78, 66, 215, 61, 156, 180}
 
+/*
+ * A lookup table to traverse the simplex around a given point in 4D.
+ * Details can be found where this table is used, in the 4D noise method.
+ */
+var simplex = [64][4]uint8{
+	{0, 1, 2, 3}, {0, 1, 3, 2}, {0, 0, 0, 0}, {0, 2, 3, 1}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {1, 2, 3, 0},
+	{0, 2, 1, 3}, {0, 0, 0, 0}, {0, 3, 1, 2}, {0, 3, 2, 1}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {1, 3, 2, 0},
+	{0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0},
+	{1, 2, 0, 3}, {0, 0, 0, 0}, {1, 3, 0, 2}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {2, 3, 0, 1}, {2, 3, 1, 0},
+	{1, 0, 2, 3}, {1, 0, 3, 2}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {2, 0, 3, 1}, {0, 0, 0, 0}, {2, 1, 3, 0},
+	{0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0},
+	{2, 0, 1, 3}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {3, 0, 1, 2}, {3, 0, 2, 1}, {0, 0, 0, 0}, {3, 1, 2, 0},
+	{2, 1, 0, 3}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {3, 1, 0, 2}, {0, 0, 0, 0}, {3, 2, 0, 1}, {3, 2, 1, 0}}
+
 //---------------------------------------------------------------------
 
+/*
+ * Helper functions to compute gradients-dot-residualvectors (1D to 4D)
+ * Note that these generate gradients of more than unit length. To make
+ * a close match with the value range of classic Perlin noise, the final
+ * noise values need to be rescaled to fit nicely within [-1,1].
+ * (The simplex noise functions as such also have different scaling.)
+ * Note also that these noise functions are the most practical and useful
+ * signed version of Perlin noise. To return values according to the
+ * RenderMan specification from the SL noise() and pnoise() functions,
+ * the noise values need to be scaled and offset to [0,1], like this:
+ * float SLnoise = (noise(x,y,z) + 1.0) * 0.5;
+ */
+
+func grad1(hash uint8, x float32) float32 {
+	h := hash & 15
+	grad := 1.0 + float32(h&7) // Gradient value 1.0, 2.0, ..., 8.0
+	if h&8 != 0 {
+		grad = -grad // Set a random sign for the gradient
+	}
+	return grad * x // Multiply the gradient with the distance
+}
+
 func grad2(hash uint8, x, y float32) float32 {
 	h := hash & 7 // Convert low 3 bits of hash code
 	u := y
@@ -82,8 +125,10 @@ func grad2(hash uint8, x, y float32) float32 {
 	return u + v
 }
 
-// SNoise2 2D simplex noise
-func SNoise2(x, y float32) float32 {
+// Noise2 returns the 2D simplex noise at the given point using the
+// generator's permutation table
+func (g *Generator) Noise2(x, y float32) float32 {
+	perm := &g.perm
 
 	const F2 float32 = 0.366025403 // F2 = 0.5*(sqrt(3.0)-1.0)
 	const G2 float32 = 0.211324865 // G2 = (3.0-Math.sqrt(3.0))/6.0
@@ -91,13 +136,13 @@ func SNoise2(x, y float32) float32 {
 	var n0, n1, n2 float32 // Noise contributions from the three corners
 
 	// Skew the input space to determine which simplex cell we're in
-	s := (x + y) * F2 // Hairy factor for 2D
+	s := float32((x + y) * F2) // Hairy factor for 2D
 	xs := x + s
 	ys := y + s
 	i := fastFloor(xs)
 	j := fastFloor(ys)
 
-	t := float32(i+j) * G2
+	t := float32(float32(i+j) * G2)
 	X0 := float32(i) - t // Unskew the cell origin back to (x,y) space
 	Y0 := float32(j) - t
 	x0 := x - X0 // The x,y distances from the cell origin
@@ -128,7 +173,7 @@ func SNoise2(x, y float32) float32 {
 	jj := uint8(j)
 
 	// Calculate the contribution from the three corners
-	t0 := 0.5 - x0*x0 - y0*y0
+	t0 := 0.5 - float32(x0*x0) - float32(y0*y0)
 	if t0 < 0.0 {
 		n0 = 0.0
 	} else {
@@ -136,7 +181,7 @@ func SNoise2(x, y float32) float32 {
 		n0 = t0 * t0 * grad2(perm[ii+perm[jj]], x0, y0)
 	}
 
-	t1 := 0.5 - x1*x1 - y1*y1
+	t1 := 0.5 - float32(x1*x1) - float32(y1*y1)
 	if t1 < 0.0 {
 		n1 = 0.0
 	} else {
@@ -144,7 +189,7 @@ func SNoise2(x, y float32) float32 {
 		n1 = t1 * t1 * grad2(perm[ii+i1+perm[jj+j1]], x1, y1)
 	}
 
-	t2 := 0.5 - x2*x2 - y2*y2
+	t2 := 0.5 - float32(x2*x2) - float32(y2*y2)
 	if t2 < 0.0 {
 		n2 = 0.0
 	} else {
@@ -155,3 +200,349 @@ func SNoise2(x, y float32) float32 {
 	// Add contributions from each corner to get the final noise value.
 	return (n0 + n1 + n2)
 }
+
+func grad3(hash uint8, x, y, z float32) float32 {
+	h := hash & 15 // Convert low 4 bits of hash code into 12 simple
+	u := y         // gradient directions, and compute dot product.
+	if h < 8 {
+		u = x
+	}
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 { // Fix repeats at h = 12 to 15
+		v = x
+	}
+
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+func grad4(hash uint8, x, y, z, t float32) float32 {
+	h := hash & 31 // Convert low 5 bits of hash code into 32 simple
+	u := y         // gradient directions, and compute dot product.
+	if h < 24 {
+		u = x
+	}
+	v := z
+	if h < 16 {
+		v = y
+	}
+	w := t
+	if h < 8 {
+		w = z
+	}
+
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	if h&4 != 0 {
+		w = -w
+	}
+	return u + v + w
+}
+
+// Noise1 returns the 1D simplex noise at the given point using the
+// generator's permutation table
+func (g *Generator) Noise1(x float32) float32 {
+	perm := &g.perm
+	i0 := fastFloor(x)
+	i1 := i0 + 1
+	x0 := x - float32(i0)
+	x1 := x0 - 1.0
+
+	t0 := 1.0 - float32(x0*x0)
+	// if t0 < 0.0 { t0 = 0.0 } // this never happens for the 1D case
+	t0 *= t0
+	n0 := float32(t0 * t0 * grad1(perm[uint8(i0)], x0))
+
+	t1 := 1.0 - float32(x1*x1)
+	// if t1 < 0.0 { t1 = 0.0 } // this never happens for the 1D case
+	t1 *= t1
+	n1 := float32(t1 * t1 * grad1(perm[uint8(i1)], x1))
+
+	// The maximum value of this noise is 8*(3/4)^4 = 2.53125
+	// A factor of 0.395 would scale to fit exactly within [-1,1], but
+	// we want to match PRMan's 1D noise, so we scale it down some more.
+	return 0.25 * (n0 + n1)
+}
+
+// Noise3 returns the 3D simplex noise at the given point using the
+// generator's permutation table
+func (g *Generator) Noise3(x, y, z float32) float32 {
+	perm := &g.perm
+
+	// Simple skewing factors for the 3D case. Like the reference C macros
+	// these are double precision, so the skew and unskew steps below are
+	// computed in float64 and rounded back to float32 to match its output.
+	const F3 = 0.333333333
+	const G3 = 0.166666667
+
+	var n0, n1, n2, n3 float32 // Noise contributions from the four corners
+
+	// Skew the input space to determine which simplex cell we're in
+	s := float32(float64(x+y+z) * F3) // Very nice and simple skew factor for 3D
+	xs := x + s
+	ys := y + s
+	zs := z + s
+	i := fastFloor(xs)
+	j := fastFloor(ys)
+	k := fastFloor(zs)
+
+	t := float32(float64(float32(i+j+k)) * G3)
+	X0 := float32(i) - t // Unskew the cell origin back to (x,y,z) space
+	Y0 := float32(j) - t
+	Z0 := float32(k) - t
+	x0 := x - X0 // The x,y,z distances from the cell origin
+	y0 := y - Y0
+	z0 := z - Z0
+
+	// For the 3D case, the simplex shape is a slightly irregular tetrahedron.
+	// Determine which simplex we are in.
+	var i1, j1, k1 uint8 // Offsets for second corner of simplex in (i,j,k) coords
+	var i2, j2, k2 uint8 // Offsets for third corner of simplex in (i,j,k) coords
+
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0 // X Y Z order
+		} else if x0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1 // X Z Y order
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1 // Z X Y order
+		}
+	} else { // x0<y0
+		if y0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1 // Z Y X order
+		} else if x0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1 // Y Z X order
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0 // Y X Z order
+		}
+	}
+
+	// A step of (1,0,0) in (i,j,k) means a step of (1-c,-c,-c) in (x,y,z),
+	// a step of (0,1,0) in (i,j,k) means a step of (-c,1-c,-c) in (x,y,z), and
+	// a step of (0,0,1) in (i,j,k) means a step of (-c,-c,1-c) in (x,y,z), where
+	// c = 1/6.
+
+	x1 := float32(float64(x0-float32(i1)) + G3) // Offsets for second corner in (x,y,z) coords
+	y1 := float32(float64(y0-float32(j1)) + G3)
+	z1 := float32(float64(z0-float32(k1)) + G3)
+	x2 := float32(float64(x0-float32(i2)) + 2.0*G3) // Offsets for third corner in (x,y,z) coords
+	y2 := float32(float64(y0-float32(j2)) + 2.0*G3)
+	z2 := float32(float64(z0-float32(k2)) + 2.0*G3)
+	x3 := float32(float64(x0-1.0) + 3.0*G3) // Offsets for last corner in (x,y,z) coords
+	y3 := float32(float64(y0-1.0) + 3.0*G3)
+	z3 := float32(float64(z0-1.0) + 3.0*G3)
+
+	// Wrap the integer indices at 256, to avoid indexing perm[] out of bounds
+	ii := uint8(i)
+	jj := uint8(j)
+	kk := uint8(k)
+
+	// Calculate the contribution from the four corners
+	t0 := 0.6 - float32(x0*x0) - float32(y0*y0) - float32(z0*z0)
+	if t0 < 0.0 {
+		n0 = 0.0
+	} else {
+		t0 *= t0
+		n0 = t0 * t0 * grad3(perm[ii+perm[jj+perm[kk]]], x0, y0, z0)
+	}
+
+	t1 := 0.6 - float32(x1*x1) - float32(y1*y1) - float32(z1*z1)
+	if t1 < 0.0 {
+		n1 = 0.0
+	} else {
+		t1 *= t1
+		n1 = t1 * t1 * grad3(perm[ii+i1+perm[jj+j1+perm[kk+k1]]], x1, y1, z1)
+	}
+
+	t2 := 0.6 - float32(x2*x2) - float32(y2*y2) - float32(z2*z2)
+	if t2 < 0.0 {
+		n2 = 0.0
+	} else {
+		t2 *= t2
+		n2 = t2 * t2 * grad3(perm[ii+i2+perm[jj+j2+perm[kk+k2]]], x2, y2, z2)
+	}
+
+	t3 := 0.6 - float32(x3*x3) - float32(y3*y3) - float32(z3*z3)
+	if t3 < 0.0 {
+		n3 = 0.0
+	} else {
+		t3 *= t3
+		n3 = t3 * t3 * grad3(perm[ii+1+perm[jj+1+perm[kk+1]]], x3, y3, z3)
+	}
+
+	// Add contributions from each corner to get the final noise value.
+	// The result is scaled to stay just inside [-1,1]
+	return 32.0 * (n0 + n1 + n2 + n3)
+}
+
+// Noise4 returns the 4D simplex noise at the given point using the
+// generator's permutation table
+func (g *Generator) Noise4(x, y, z, w float32) float32 {
+	perm := &g.perm
+
+	// The skewing and unskewing factors are hairy again for the 4D case.
+	// As in SNoise3 they are applied in double precision.
+	const F4 = 0.309016994 // F4 = (Math.sqrt(5.0)-1.0)/4.0
+	const G4 = 0.138196601 // G4 = (5.0-Math.sqrt(5.0))/20.0
+
+	var n0, n1, n2, n3, n4 float32 // Noise contributions from the five corners
+
+	// Skew the (x,y,z,w) space to determine which cell of 24 simplices we're in
+	s := float32(float64(x+y+z+w) * F4) // Factor for 4D skewing
+	xs := x + s
+	ys := y + s
+	zs := z + s
+	ws := w + s
+	i := fastFloor(xs)
+	j := fastFloor(ys)
+	k := fastFloor(zs)
+	l := fastFloor(ws)
+
+	t := float32(float64(i+j+k+l) * G4) // Factor for 4D unskewing
+	X0 := float32(i) - t                // Unskew the cell origin back to (x,y,z,w) space
+	Y0 := float32(j) - t
+	Z0 := float32(k) - t
+	W0 := float32(l) - t
+
+	x0 := x - X0 // The x,y,z,w distances from the cell origin
+	y0 := y - Y0
+	z0 := z - Z0
+	w0 := w - W0
+
+	// For the 4D case, the simplex is a 4D shape I won't even try to describe.
+	// To find out which of the 24 possible simplices we're in, we need to
+	// determine the magnitude ordering of x0, y0, z0 and w0.
+	// The method below is a good way of finding the ordering of x,y,z,w and
+	// then find the correct traversal order for the simplex we're in.
+	// First, six pair-wise comparisons are performed between each possible pair
+	// of the four coordinates, and the results are used to add up binary bits
+	// for an integer index.
+	c := 0
+	if x0 > y0 {
+		c += 32
+	}
+	if x0 > z0 {
+		c += 16
+	}
+	if y0 > z0 {
+		c += 8
+	}
+	if x0 > w0 {
+		c += 4
+	}
+	if y0 > w0 {
+		c += 2
+	}
+	if z0 > w0 {
+		c++
+	}
+
+	// simplex[c] is a 4-vector with the numbers 0, 1, 2 and 3 in some order.
+	// Many values of c will never occur, since e.g. x>y>z>w makes x<z, y<w and x<w
+	// impossible. Only the 24 indices which have non-zero entries make any sense.
+	// We use a thresholding to set the coordinates in turn from the largest magnitude.
+	sc := simplex[c]
+	// The number 3 in the "simplex" array is at the position of the largest coordinate.
+	i1, j1, k1, l1 := threshold(sc, 3)
+	// The number 2 in the "simplex" array is at the second largest coordinate.
+	i2, j2, k2, l2 := threshold(sc, 2)
+	// The number 1 in the "simplex" array is at the second smallest coordinate.
+	i3, j3, k3, l3 := threshold(sc, 1)
+	// The fifth corner has all coordinate offsets = 1, so no need to look that up.
+
+	x1 := float32(float64(x0-float32(i1)) + G4) // Offsets for second corner in (x,y,z,w) coords
+	y1 := float32(float64(y0-float32(j1)) + G4)
+	z1 := float32(float64(z0-float32(k1)) + G4)
+	w1 := float32(float64(w0-float32(l1)) + G4)
+	x2 := float32(float64(x0-float32(i2)) + 2.0*G4) // Offsets for third corner in (x,y,z,w) coords
+	y2 := float32(float64(y0-float32(j2)) + 2.0*G4)
+	z2 := float32(float64(z0-float32(k2)) + 2.0*G4)
+	w2 := float32(float64(w0-float32(l2)) + 2.0*G4)
+	x3 := float32(float64(x0-float32(i3)) + 3.0*G4) // Offsets for fourth corner in (x,y,z,w) coords
+	y3 := float32(float64(y0-float32(j3)) + 3.0*G4)
+	z3 := float32(float64(z0-float32(k3)) + 3.0*G4)
+	w3 := float32(float64(w0-float32(l3)) + 3.0*G4)
+	x4 := float32(float64(x0-1.0) + 4.0*G4) // Offsets for last corner in (x,y,z,w) coords
+	y4 := float32(float64(y0-1.0) + 4.0*G4)
+	z4 := float32(float64(z0-1.0) + 4.0*G4)
+	w4 := float32(float64(w0-1.0) + 4.0*G4)
+
+	// Wrap the integer indices at 256, to avoid indexing perm[] out of bounds
+	ii := uint8(i)
+	jj := uint8(j)
+	kk := uint8(k)
+	ll := uint8(l)
+
+	// Calculate the contribution from the five corners
+	t0 := 0.6 - float32(x0*x0) - float32(y0*y0) - float32(z0*z0) - float32(w0*w0)
+	if t0 < 0.0 {
+		n0 = 0.0
+	} else {
+		t0 *= t0
+		n0 = t0 * t0 * grad4(perm[ii+perm[jj+perm[kk+perm[ll]]]], x0, y0, z0, w0)
+	}
+
+	t1 := 0.6 - float32(x1*x1) - float32(y1*y1) - float32(z1*z1) - float32(w1*w1)
+	if t1 < 0.0 {
+		n1 = 0.0
+	} else {
+		t1 *= t1
+		n1 = t1 * t1 * grad4(perm[ii+i1+perm[jj+j1+perm[kk+k1+perm[ll+l1]]]], x1, y1, z1, w1)
+	}
+
+	t2 := 0.6 - float32(x2*x2) - float32(y2*y2) - float32(z2*z2) - float32(w2*w2)
+	if t2 < 0.0 {
+		n2 = 0.0
+	} else {
+		t2 *= t2
+		n2 = t2 * t2 * grad4(perm[ii+i2+perm[jj+j2+perm[kk+k2+perm[ll+l2]]]], x2, y2, z2, w2)
+	}
+
+	t3 := 0.6 - float32(x3*x3) - float32(y3*y3) - float32(z3*z3) - float32(w3*w3)
+	if t3 < 0.0 {
+		n3 = 0.0
+	} else {
+		t3 *= t3
+		n3 = t3 * t3 * grad4(perm[ii+i3+perm[jj+j3+perm[kk+k3+perm[ll+l3]]]], x3, y3, z3, w3)
+	}
+
+	t4 := 0.6 - float32(x4*x4) - float32(y4*y4) - float32(z4*z4) - float32(w4*w4)
+	if t4 < 0.0 {
+		n4 = 0.0
+	} else {
+		t4 *= t4
+		n4 = t4 * t4 * grad4(perm[ii+1+perm[jj+1+perm[kk+1+perm[ll+1]]]], x4, y4, z4, w4)
+	}
+
+	// Sum up and scale the result to cover the range [-1,1]
+	return 27.0 * (n0 + n1 + n2 + n3 + n4)
+}
+
+// threshold returns 1 for each coordinate of the simplex traversal order
+// that is at least n, and 0 otherwise
+func threshold(order [4]uint8, n uint8) (i, j, k, l uint8) {
+	if order[0] >= n {
+		i = 1
+	}
+	if order[1] >= n {
+		j = 1
+	}
+	if order[2] >= n {
+		k = 1
+	}
+	if order[3] >= n {
+		l = 1
+	}
+	return i, j, k, l
+}
@@ -0,0 +1,45 @@
+package simplex
+
+import (
+	"math"
+	"testing"
+)
+
+// reference holds the outputs of Gustavson's simplexnoise1234 C code with
+// its default permutation table, as float32 bit patterns
+var reference = []struct {
+	x, y, z, w             float32
+	noise1, noise3, noise4 uint32
+}{
+	{0.3, 1.7, -2.2, 4.1, 0x3ecc99c4, 0xbec38b38, 0x3d538f23},
+	{-12.5, 3.25, 0.75, -8, 0xbe4a8000, 0x3f11d873, 0x3e002f9e},
+	{101.1, -57.3, 23.9, 0.05, 0xbcc72118, 0xbe8f31d2, 0xbf383672},
+	{0.5, 0.5, 0.5, 0.5, 0x3e8dc000, 0x00000000, 0xbe2f79da},
+	{-0.9, -3.3, 7.7, 2.2, 0x3df11bc7, 0x3e816451, 0x3d913fd8},
+	{250.125, -199.5, 61.75, -30.3, 0xbe545a3c, 0x3e754b51, 0xbe835599},
+}
+
+func TestReference(t *testing.T) {
+	for _, r := range reference {
+		if got := SNoise1(r.x); math.Float32bits(got) != r.noise1 {
+			t.Errorf("SNoise1(%v) = %v, want %v", r.x, got, math.Float32frombits(r.noise1))
+		}
+		if got := SNoise3(r.x, r.y, r.z); math.Float32bits(got) != r.noise3 {
+			t.Errorf("SNoise3(%v, %v, %v) = %v, want %v", r.x, r.y, r.z, got, math.Float32frombits(r.noise3))
+		}
+		if got := SNoise4(r.x, r.y, r.z, r.w); math.Float32bits(got) != r.noise4 {
+			t.Errorf("SNoise4(%v, %v, %v, %v) = %v, want %v", r.x, r.y, r.z, r.w, got, math.Float32frombits(r.noise4))
+		}
+	}
+}
+
+func TestDerivMatchesNoise(t *testing.T) {
+	for _, r := range reference {
+		if got, _, _ := SNoise2Deriv(r.x, r.y); math.Float32bits(got) != math.Float32bits(SNoise2(r.x, r.y)) {
+			t.Errorf("SNoise2Deriv(%v, %v) = %v, want %v", r.x, r.y, got, SNoise2(r.x, r.y))
+		}
+		if got, _, _, _ := SNoise3Deriv(r.x, r.y, r.z); math.Float32bits(got) != math.Float32bits(SNoise3(r.x, r.y, r.z)) {
+			t.Errorf("SNoise3Deriv(%v, %v, %v) = %v, want %v", r.x, r.y, r.z, got, SNoise3(r.x, r.y, r.z))
+		}
+	}
+}
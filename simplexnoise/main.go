@@ -1,8 +1,11 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 
+	"github.com/dikaeinstein/games-with-go/color"
+	"github.com/dikaeinstein/games-with-go/dither"
 	"github.com/dikaeinstein/games-with-go/noise"
 	"github.com/veandco/go-sdl2/sdl"
 )
@@ -10,11 +13,33 @@ import (
 const winWidth = 800
 const winHeight = 600
 
-type color struct {
-	r, g, b byte
-}
-
 func main() {
+	palette := flag.String("palette", "", "gradient palette file (.ggr, .json or .png)")
+	ditherName := flag.String("dither", "none", "dithering: none, bayer, bluenoise or floyd-steinberg")
+	flag.Parse()
+
+	method, err := dither.ParseMethod(*ditherName)
+	if err != nil {
+		fmt.Println("Could not parse dithering method:", err)
+		return
+	}
+
+	gradient := color.NewGradient(
+		color.Stop{Pos: 0, Color: color.RGB{R: 0, G: 0, B: 175}},
+		color.Stop{Pos: 0.5, Color: color.RGB{R: 80, G: 160, B: 244}},
+		color.Stop{Pos: 0.5, Color: color.RGB{R: 12, G: 192, B: 75}},
+		color.Stop{Pos: 1, Color: color.RGB{R: 255, G: 255, B: 255}},
+	)
+	if *palette != "" {
+		g, err := color.LoadGradient(*palette)
+		if err != nil {
+			fmt.Println("Could not load palette:", err)
+			return
+		}
+		gradient = g
+	}
+	lut := gradient.Bake(256)
+
 	if err := sdl.Init(sdl.INIT_EVERYTHING); err != nil {
 		panic(err)
 	}
@@ -50,13 +75,8 @@ func main() {
 	var gain float32 = 0.2
 	var lacunarity float32 = 3.0
 	n := noise.MakeNoise(noise.TURBULENCE, winWidth, winHeight, frequency, lacunarity, gain, octaves)
-	scale := noise.CalcScale(255.0)
-	n.Rescale(scale)
-	gradient := getDualGradient(
-		color{0, 0, 175}, color{80, 160, 244},
-		color{12, 192, 75}, color{255, 255, 255},
-	)
-	drawNoise(n, gradient, pixels)
+	n.Normalize(0, 1)
+	drawNoise(n.Values, lut, method, pixels)
 
 	keyboardState := sdl.GetKeyboardState()
 	running := true
@@ -78,33 +98,29 @@ func main() {
 		if keyboardState[sdl.SCANCODE_O] != 0 {
 			octaves = octaves + 1*int(mult)
 			n := noise.MakeNoise(noise.TURBULENCE, winWidth, winHeight, frequency, lacunarity, gain, octaves)
-			scale := noise.CalcScale(255.0)
-			n.Rescale(scale)
-			drawNoise(n, gradient, pixels)
+			n.Normalize(0, 1)
+			drawNoise(n.Values, lut, method, pixels)
 		}
 
 		if keyboardState[sdl.SCANCODE_F] != 0 {
 			frequency = frequency + float32(0.001)*mult
 			n := noise.MakeNoise(noise.TURBULENCE, winWidth, winHeight, frequency, lacunarity, gain, octaves)
-			scale := noise.CalcScale(255.0)
-			n.Rescale(scale)
-			drawNoise(n, gradient, pixels)
+			n.Normalize(0, 1)
+			drawNoise(n.Values, lut, method, pixels)
 		}
 
 		if keyboardState[sdl.SCANCODE_G] != 0 {
 			gain = gain + float32(0.1)*mult
 			n := noise.MakeNoise(noise.TURBULENCE, winWidth, winHeight, frequency, lacunarity, gain, octaves)
-			scale := noise.CalcScale(255.0)
-			n.Rescale(scale)
-			drawNoise(n, gradient, pixels)
+			n.Normalize(0, 1)
+			drawNoise(n.Values, lut, method, pixels)
 		}
 
 		if keyboardState[sdl.SCANCODE_L] != 0 {
 			lacunarity = lacunarity + float32(0.1)*mult
 			n := noise.MakeNoise(noise.TURBULENCE, winWidth, winHeight, frequency, lacunarity, gain, octaves)
-			scale := noise.CalcScale(255.0)
-			n.Rescale(scale)
-			drawNoise(n, gradient, pixels)
+			n.Normalize(0, 1)
+			drawNoise(n.Values, lut, method, pixels)
 		}
 
 		tex.Update(nil, pixels, winWidth*4)
@@ -115,62 +131,7 @@ func main() {
 	}
 }
 
-func lerp(b1, b2 byte, pct float32) byte {
-	return uint8(float32(b1) + pct*(float32(b2)-float32(b1)))
-}
-
-func colorLerp(c1, c2 color, pct float32) color {
-	return color{
-		lerp(c1.r, c2.r, pct),
-		lerp(c1.g, c2.g, pct),
-		lerp(c1.b, c2.b, pct),
-	}
-}
-
-func getGradient(c1, c2 color) []color {
-	result := make([]color, 256)
-
-	for i := range result {
-		pct := float32(i) / float32(255)
-		result[i] = colorLerp(c1, c2, pct)
-	}
-
-	return result
-}
-
-func getDualGradient(c1, c2, c3, c4 color) []color {
-	result := make([]color, 256)
-
-	for i := range result {
-		pct := float32(i) / float32(255)
-		if pct < 0.5 {
-			result[i] = colorLerp(c1, c2, pct*float32(2))
-		} else {
-			result[i] = colorLerp(c3, c4, pct*float32(1.5)-float32(0.5))
-		}
-	}
-
-	return result
-}
-
-// clamp ensure v is within this interval or boundary
-func clamp(min, max, v int) int {
-	if v < min {
-		min = v
-	} else if v > max {
-		max = v
-	}
-
-	return v
-}
-
-// drawNoise draws noise to the pixels buffer
-func drawNoise(noise []float32, gradient []color, pixels []byte) {
-	for i := range noise {
-		c := gradient[clamp(0, 255, int(noise[i]))]
-		p := i * 4
-		pixels[p] = c.r
-		pixels[p+1] = c.g
-		pixels[p+2] = c.b
-	}
+// drawNoise draws noise in [0,1] to the pixels buffer
+func drawNoise(noise []float32, gradient color.LUT, method dither.Method, pixels []byte) {
+	dither.Field(noise, winWidth, gradient, method, pixels)
 }
@@ -0,0 +1,168 @@
+package spatial
+
+import (
+	"math"
+
+	"github.com/dikaeinstein/games-with-go/geometry"
+	"github.com/dikaeinstein/games-with-go/vector"
+	"github.com/dikaeinstein/games-with-go/vector/generic"
+)
+
+// Grid is a uniform grid of square cells, stored in a hash map so it is
+// unbounded. Each object is listed in every cell its box overlaps, so the
+// cell size should be around the size of the objects.
+type Grid struct {
+	cellSize float32
+	cells    map[cell][]ID
+	objects  map[ID]*gridObject
+	// mark is bumped by every query so an object listed in several cells
+	// is only reported once
+	mark uint32
+}
+
+type cell = generic.Vec2[int32]
+
+type gridObject struct {
+	bounds     geometry.AABB
+	min, max   cell
+	lastMarked uint32
+}
+
+// NewGrid creates an empty Grid with the given cell size
+func NewGrid(cellSize float32) *Grid {
+	return &Grid{
+		cellSize: cellSize,
+		cells:    make(map[cell][]ID),
+		objects:  make(map[ID]*gridObject),
+	}
+}
+
+// cellOf returns the cell holding p
+func (g *Grid) cellOf(p vector.Vec2) cell {
+	return cell{
+		X: int32(math.Floor(float64(p.X / g.cellSize))),
+		Y: int32(math.Floor(float64(p.Y / g.cellSize))),
+	}
+}
+
+// Len returns the number of objects in the grid
+func (g *Grid) Len() int {
+	return len(g.objects)
+}
+
+// Insert adds the object id with the given bounds, or moves it if it is
+// already in the grid
+func (g *Grid) Insert(id ID, bounds geometry.AABB) {
+	if _, ok := g.objects[id]; ok {
+		g.Move(id, bounds)
+		return
+	}
+
+	o := &gridObject{bounds: bounds, min: g.cellOf(bounds.Min), max: g.cellOf(bounds.Max)}
+	g.objects[id] = o
+	g.add(id, o)
+}
+
+// Move updates the bounds of the object id. It only touches the cells
+// when the object crosses a cell border.
+func (g *Grid) Move(id ID, bounds geometry.AABB) {
+	o, ok := g.objects[id]
+	if !ok {
+		g.Insert(id, bounds)
+		return
+	}
+
+	o.bounds = bounds
+	min, max := g.cellOf(bounds.Min), g.cellOf(bounds.Max)
+	if min == o.min && max == o.max {
+		return
+	}
+	g.remove(id, o)
+	o.min, o.max = min, max
+	g.add(id, o)
+}
+
+// Remove deletes the object id from the grid
+func (g *Grid) Remove(id ID) {
+	if o, ok := g.objects[id]; ok {
+		g.remove(id, o)
+		delete(g.objects, id)
+	}
+}
+
+func (g *Grid) add(id ID, o *gridObject) {
+	for y := o.min.Y; y <= o.max.Y; y++ {
+		for x := o.min.X; x <= o.max.X; x++ {
+			c := cell{X: x, Y: y}
+			g.cells[c] = append(g.cells[c], id)
+		}
+	}
+}
+
+func (g *Grid) remove(id ID, o *gridObject) {
+	for y := o.min.Y; y <= o.max.Y; y++ {
+		for x := o.min.X; x <= o.max.X; x++ {
+			c := cell{X: x, Y: y}
+			ids := g.cells[c]
+			for i, other := range ids {
+				if other == id {
+					ids[i] = ids[len(ids)-1]
+					ids = ids[:len(ids)-1]
+					break
+				}
+			}
+			if len(ids) == 0 {
+				delete(g.cells, c)
+			} else {
+				g.cells[c] = ids
+			}
+		}
+	}
+}
+
+// QueryPoint appends the objects whose box contains p to dst
+func (g *Grid) QueryPoint(p vector.Vec2, dst []ID) []ID {
+	return g.query(pointQuery(p), dst)
+}
+
+// QueryBox appends the objects whose box overlaps box to dst
+func (g *Grid) QueryBox(box geometry.AABB, dst []ID) []ID {
+	return g.query(boxQuery(box), dst)
+}
+
+// QueryRadius appends the objects whose box is within radius of center
+// to dst
+func (g *Grid) QueryRadius(center vector.Vec2, radius float32, dst []ID) []ID {
+	return g.query(radiusQuery(center, radius), dst)
+}
+
+func (g *Grid) query(q query, dst []ID) []ID {
+	g.mark++
+	min, max := g.cellOf(q.bounds.Min), g.cellOf(q.bounds.Max)
+
+	// a huge query visits fewer entries by going through the objects
+	if int64(max.X-min.X+1)*int64(max.Y-min.Y+1) > int64(len(g.cells)) {
+		for id, o := range g.objects {
+			if q.test(o.bounds) {
+				dst = append(dst, id)
+			}
+		}
+		return dst
+	}
+
+	for y := min.Y; y <= max.Y; y++ {
+		for x := min.X; x <= max.X; x++ {
+			for _, id := range g.cells[cell{X: x, Y: y}] {
+				o := g.objects[id]
+				if o.lastMarked == g.mark {
+					continue
+				}
+				o.lastMarked = g.mark
+				if q.test(o.bounds) {
+					dst = append(dst, id)
+				}
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,202 @@
+package spatial
+
+import (
+	"github.com/dikaeinstein/games-with-go/geometry"
+	"github.com/dikaeinstein/games-with-go/vector"
+)
+
+// Quadtree is a loose quadtree over fixed world bounds. The loose bounds
+// of every node are twice the size of the area it splits, so an object
+// is stored in a single node, picked from its size and center, and never
+// needs splitting. Objects outside the world bounds are kept in the root.
+type Quadtree struct {
+	root     *quadNode
+	maxDepth int
+	objects  map[ID]*quadObject
+}
+
+type quadNode struct {
+	bounds   geometry.AABB
+	loose    geometry.AABB
+	depth    int
+	parent   *quadNode
+	children [4]*quadNode
+	ids      []ID
+}
+
+type quadObject struct {
+	bounds geometry.AABB
+	node   *quadNode
+}
+
+// NewQuadtree creates an empty Quadtree covering world, which nodes split
+// at most maxDepth times
+func NewQuadtree(world geometry.AABB, maxDepth int) *Quadtree {
+	return &Quadtree{
+		root:     newQuadNode(world, 0, nil),
+		maxDepth: maxDepth,
+		objects:  make(map[ID]*quadObject),
+	}
+}
+
+func newQuadNode(bounds geometry.AABB, depth int, parent *quadNode) *quadNode {
+	half := bounds.Size().Scale(0.5)
+	return &quadNode{
+		bounds: bounds,
+		loose:  geometry.AABB{Min: bounds.Min.Sub(half), Max: bounds.Max.Add(half)},
+		depth:  depth,
+		parent: parent,
+	}
+}
+
+// Len returns the number of objects in the tree
+func (t *Quadtree) Len() int {
+	return len(t.objects)
+}
+
+// Insert adds the object id with the given bounds, or moves it if it is
+// already in the tree
+func (t *Quadtree) Insert(id ID, bounds geometry.AABB) {
+	if _, ok := t.objects[id]; ok {
+		t.Move(id, bounds)
+		return
+	}
+
+	n := t.nodeFor(bounds)
+	n.ids = append(n.ids, id)
+	t.objects[id] = &quadObject{bounds, n}
+}
+
+// Move updates the bounds of the object id. It stays in its node while
+// the node loose bounds still hold it and it is too big for the children.
+func (t *Quadtree) Move(id ID, bounds geometry.AABB) {
+	o, ok := t.objects[id]
+	if !ok {
+		t.Insert(id, bounds)
+		return
+	}
+
+	o.bounds = bounds
+	if n := t.nodeFor(bounds); n != o.node {
+		// add to the new node first, so removing from the old one does
+		// not prune it when it is an otherwise empty ancestor
+		n.ids = append(n.ids, id)
+		o.node.remove(id)
+		o.node = n
+	}
+}
+
+// Remove deletes the object id from the tree
+func (t *Quadtree) Remove(id ID) {
+	if o, ok := t.objects[id]; ok {
+		o.node.remove(id)
+		delete(t.objects, id)
+	}
+}
+
+// nodeFor returns the deepest node whose loose bounds hold bounds,
+// creating the nodes on the way
+func (t *Quadtree) nodeFor(bounds geometry.AABB) *quadNode {
+	n := t.root
+	size := bounds.Size()
+	center := bounds.Center()
+	if !n.bounds.Contains(center) {
+		return n
+	}
+
+	for n.depth < t.maxDepth {
+		// the loose bounds of a child hold any object centered in it that
+		// is no bigger than the child itself
+		childSize := n.bounds.Size().Scale(0.5)
+		if size.X > childSize.X || size.Y > childSize.Y {
+			break
+		}
+
+		i, childBounds := n.quadrant(center)
+		if n.children[i] == nil {
+			n.children[i] = newQuadNode(childBounds, n.depth+1, n)
+		}
+		n = n.children[i]
+	}
+	return n
+}
+
+// quadrant returns the index and the bounds of the child of n holding p
+func (n *quadNode) quadrant(p vector.Vec2) (int, geometry.AABB) {
+	mid := n.bounds.Center()
+	b := geometry.AABB{Min: n.bounds.Min, Max: mid}
+	i := 0
+	if p.X >= mid.X {
+		i |= 1
+		b.Min.X, b.Max.X = mid.X, n.bounds.Max.X
+	}
+	if p.Y >= mid.Y {
+		i |= 2
+		b.Min.Y, b.Max.Y = mid.Y, n.bounds.Max.Y
+	}
+	return i, b
+}
+
+// remove deletes id from the node, then frees the node and its empty
+// parents
+func (n *quadNode) remove(id ID) {
+	for i, other := range n.ids {
+		if other == id {
+			n.ids[i] = n.ids[len(n.ids)-1]
+			n.ids = n.ids[:len(n.ids)-1]
+			break
+		}
+	}
+
+	for n.parent != nil && n.empty() {
+		p := n.parent
+		for i, c := range p.children {
+			if c == n {
+				p.children[i] = nil
+			}
+		}
+		n = p
+	}
+}
+
+func (n *quadNode) empty() bool {
+	if len(n.ids) > 0 {
+		return false
+	}
+	for _, c := range n.children {
+		if c != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryPoint appends the objects whose box contains p to dst
+func (t *Quadtree) QueryPoint(p vector.Vec2, dst []ID) []ID {
+	return t.query(t.root, pointQuery(p), dst)
+}
+
+// QueryBox appends the objects whose box overlaps box to dst
+func (t *Quadtree) QueryBox(box geometry.AABB, dst []ID) []ID {
+	return t.query(t.root, boxQuery(box), dst)
+}
+
+// QueryRadius appends the objects whose box is within radius of center
+// to dst
+func (t *Quadtree) QueryRadius(center vector.Vec2, radius float32, dst []ID) []ID {
+	return t.query(t.root, radiusQuery(center, radius), dst)
+}
+
+func (t *Quadtree) query(n *quadNode, q query, dst []ID) []ID {
+	for _, id := range n.ids {
+		if q.test(t.objects[id].bounds) {
+			dst = append(dst, id)
+		}
+	}
+	for _, c := range n.children {
+		if c != nil && overlaps(c.loose, q.bounds) {
+			dst = t.query(c, q, dst)
+		}
+	}
+	return dst
+}
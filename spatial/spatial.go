@@ -0,0 +1,76 @@
+// Package spatial indexes the bounding boxes of many objects so the ones
+// near a point, a box or a circle can be found without testing them all.
+//
+// Grid hashes the boxes into uniform cells, which suits objects of similar
+// size. Quadtree is a loose quadtree, which suits objects of very
+// different sizes. Both are not safe for concurrent use.
+package spatial
+
+import (
+	"github.com/dikaeinstein/games-with-go/geometry"
+	"github.com/dikaeinstein/games-with-go/vector"
+)
+
+// ID identifies an object in an index
+type ID int
+
+// Index is a spatial index of object bounding boxes. The queries append
+// the IDs of the objects whose box overlaps the query shape to dst and
+// return it, so a buffer can be reused between frames.
+type Index interface {
+	// Insert adds the object id with the given bounds, or moves it if it
+	// is already in the index
+	Insert(id ID, bounds geometry.AABB)
+	// Move updates the bounds of the object id
+	Move(id ID, bounds geometry.AABB)
+	// Remove deletes the object id from the index
+	Remove(id ID)
+	// Len returns the number of objects in the index
+	Len() int
+
+	QueryPoint(p vector.Vec2, dst []ID) []ID
+	QueryBox(box geometry.AABB, dst []ID) []ID
+	QueryRadius(center vector.Vec2, radius float32, dst []ID) []ID
+}
+
+// PointBounds returns the empty box at p, to index objects by their
+// position only
+func PointBounds(p vector.Vector) geometry.AABB {
+	return geometry.AABB{Min: p.XY(), Max: p.XY()}
+}
+
+// overlaps reports whether the boxes a and b overlap or touch
+func overlaps(a, b geometry.AABB) bool {
+	return a.Min.X <= b.Max.X && a.Max.X >= b.Min.X &&
+		a.Min.Y <= b.Max.Y && a.Max.Y >= b.Min.Y
+}
+
+// query is the shape of a query: a box that bounds it, and an exact test
+type query struct {
+	bounds geometry.AABB
+	test   func(geometry.AABB) bool
+}
+
+func pointQuery(p vector.Vec2) query {
+	return query{geometry.AABB{Min: p, Max: p}, func(b geometry.AABB) bool {
+		return b.Contains(p)
+	}}
+}
+
+func boxQuery(box geometry.AABB) query {
+	return query{box, func(b geometry.AABB) bool {
+		return overlaps(b, box)
+	}}
+}
+
+func radiusQuery(center vector.Vec2, radius float32) query {
+	c := geometry.Circle{Center: center, Radius: radius}
+	return query{c.Bounds(), func(b geometry.AABB) bool {
+		return b.ClosestPoint(center).DistanceSquared(center) <= radius*radius
+	}}
+}
+
+var (
+	_ Index = (*Grid)(nil)
+	_ Index = (*Quadtree)(nil)
+)
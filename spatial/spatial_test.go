@@ -0,0 +1,235 @@
+package spatial
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dikaeinstein/games-with-go/geometry"
+	"github.com/dikaeinstein/games-with-go/vector"
+)
+
+var world = geometry.AABB{Max: vector.Vec2{X: 1000, Y: 1000}}
+
+// bruteForce is an Index that tests every box, the reference the indexes
+// are checked and measured against
+type bruteForce map[ID]geometry.AABB
+
+func (b bruteForce) Insert(id ID, bounds geometry.AABB) { b[id] = bounds }
+func (b bruteForce) Move(id ID, bounds geometry.AABB)   { b[id] = bounds }
+func (b bruteForce) Remove(id ID)                       { delete(b, id) }
+func (b bruteForce) Len() int                           { return len(b) }
+
+func (b bruteForce) QueryPoint(p vector.Vec2, dst []ID) []ID {
+	return b.query(pointQuery(p), dst)
+}
+
+func (b bruteForce) QueryBox(box geometry.AABB, dst []ID) []ID {
+	return b.query(boxQuery(box), dst)
+}
+
+func (b bruteForce) QueryRadius(center vector.Vec2, radius float32, dst []ID) []ID {
+	return b.query(radiusQuery(center, radius), dst)
+}
+
+func (b bruteForce) query(q query, dst []ID) []ID {
+	for id, bounds := range b {
+		if q.test(bounds) {
+			dst = append(dst, id)
+		}
+	}
+	return dst
+}
+
+var indexes = []struct {
+	name string
+	new  func() Index
+}{
+	{"grid", func() Index { return NewGrid(32) }},
+	{"quadtree", func() Index { return NewQuadtree(world, 8) }},
+	{"brute", func() Index { return bruteForce{} }},
+}
+
+// outside puts some boxes of the tests partly or wholly outside the world,
+// which the quadtree keeps in its root
+const outside = 100
+
+// randomBox returns a mostly small box, sometimes a large one, centered
+// up to margin outside the world
+func randomBox(r *rand.Rand, margin float32) geometry.AABB {
+	c := vector.Vec2{
+		X: r.Float32()*(1000+2*margin) - margin,
+		Y: r.Float32()*(1000+2*margin) - margin,
+	}
+	size := r.Float32() * 30
+	if r.Intn(20) == 0 {
+		size = r.Float32() * 400
+	}
+	return geometry.RectAABB(c, size, size*r.Float32()*2)
+}
+
+func randomPoint(r *rand.Rand) vector.Vec2 {
+	return vector.Vec2{X: r.Float32() * 1000, Y: r.Float32() * 1000}
+}
+
+func sorted(ids []ID) []ID {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func equalIDs(a, b []ID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIndexesMatchBruteForce(t *testing.T) {
+	// the last index is the brute force reference itself
+	for _, tt := range indexes[:len(indexes)-1] {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			idx, want := tt.new(), bruteForce{}
+
+			for i := 0; i < 2000; i++ {
+				b := randomBox(r, outside)
+				idx.Insert(ID(i), b)
+				want.Insert(ID(i), b)
+			}
+
+			for i := 0; i < 2000; i++ {
+				// ids past the inserted ones exercise moving and removing
+				// objects that are not in the index
+				id := ID(r.Intn(2400))
+				op := "insert"
+				switch r.Intn(3) {
+				case 0:
+					b := randomBox(r, outside)
+					idx.Insert(id, b)
+					want.Insert(id, b)
+				case 1:
+					op = "move"
+					b := randomBox(r, outside)
+					idx.Move(id, b)
+					want.Move(id, b)
+				case 2:
+					op = "remove"
+					idx.Remove(id)
+					want.Remove(id)
+				}
+
+				if idx.Len() != want.Len() {
+					t.Fatalf("after %s %d: Len() = %d, want %d", op, id, idx.Len(), want.Len())
+				}
+
+				p, box, radius := randomPoint(r), randomBox(r, outside), r.Float32()*100
+				checks := []struct {
+					query     string
+					got, want []ID
+				}{
+					{"QueryPoint", idx.QueryPoint(p, nil), want.QueryPoint(p, nil)},
+					{"QueryBox", idx.QueryBox(box, nil), want.QueryBox(box, nil)},
+					{"QueryRadius", idx.QueryRadius(p, radius, nil), want.QueryRadius(p, radius, nil)},
+				}
+				for _, c := range checks {
+					if got, want := sorted(c.got), sorted(c.want); !equalIDs(got, want) {
+						t.Fatalf("after %s %d: %s = %v, want %v", op, id, c.query, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestQuadtreeMoveToAncestor(t *testing.T) {
+	// the box grows out of its deep node into an ancestor that holds
+	// nothing else
+	q := NewQuadtree(world, 6)
+	q.Insert(1, geometry.AABB{Min: vector.Vec2{X: 10, Y: 10}, Max: vector.Vec2{X: 12, Y: 12}})
+	q.Move(1, geometry.AABB{Min: vector.Vec2{X: 10, Y: 10}, Max: vector.Vec2{X: 400, Y: 400}})
+
+	if q.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", q.Len())
+	}
+	if got := q.QueryPoint(vector.Vec2{X: 200, Y: 200}, nil); !equalIDs(got, []ID{1}) {
+		t.Errorf("QueryPoint = %v, want [1]", got)
+	}
+	if got := q.QueryBox(world, nil); !equalIDs(got, []ID{1}) {
+		t.Errorf("QueryBox = %v, want [1]", got)
+	}
+}
+
+// filled returns an index of the given kind holding n random boxes
+func filled(newIndex func() Index, n int) Index {
+	r := rand.New(rand.NewSource(1))
+	idx := newIndex()
+	for i := 0; i < n; i++ {
+		idx.Insert(ID(i), randomBox(r, 0))
+	}
+	return idx
+}
+
+func BenchmarkIndex(b *testing.B) {
+	const n = 5000
+
+	for _, tt := range indexes {
+		b.Run(tt.name+"/insert", func(b *testing.B) {
+			r := rand.New(rand.NewSource(2))
+			boxes := make([]geometry.AABB, n)
+			for i := range boxes {
+				boxes[i] = randomBox(r, 0)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				idx := tt.new()
+				for id, box := range boxes {
+					idx.Insert(ID(id), box)
+				}
+			}
+		})
+
+		b.Run(tt.name+"/move", func(b *testing.B) {
+			idx := filled(tt.new, n)
+			r := rand.New(rand.NewSource(2))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				idx.Move(ID(i%n), randomBox(r, 0))
+			}
+		})
+
+		b.Run(tt.name+"/point", func(b *testing.B) {
+			idx := filled(tt.new, n)
+			r := rand.New(rand.NewSource(2))
+			var dst []ID
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dst = idx.QueryPoint(randomPoint(r), dst[:0])
+			}
+		})
+
+		b.Run(tt.name+"/box", func(b *testing.B) {
+			idx := filled(tt.new, n)
+			r := rand.New(rand.NewSource(2))
+			var dst []ID
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dst = idx.QueryBox(randomBox(r, 0), dst[:0])
+			}
+		})
+
+		b.Run(tt.name+"/radius", func(b *testing.B) {
+			idx := filled(tt.new, n)
+			r := rand.New(rand.NewSource(2))
+			var dst []ID
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dst = idx.QueryRadius(randomPoint(r), 20, dst[:0])
+			}
+		})
+	}
+}
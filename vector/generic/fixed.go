@@ -0,0 +1,52 @@
+package generic
+
+import "strconv"
+
+// fixedShift is the number of fractional bits of Fixed
+const fixedShift = 16
+
+// Fixed is a signed 16.16 fixed point number. Its arithmetic gives the
+// same results on every platform, which keeps lockstep simulations in
+// sync. Add and subtract Fixed values with + and -. The vectors treat only
+// Fixed itself as fixed point, not types defined from it.
+type Fixed int32
+
+// FixedOne is 1 as a Fixed
+const FixedOne Fixed = 1 << fixedShift
+
+// FixedFromInt returns i as a Fixed
+func FixedFromInt(i int) Fixed {
+	return Fixed(i << fixedShift)
+}
+
+// FixedFromFloat returns f as a Fixed, rounded to the nearest step
+func FixedFromFloat(f float64) Fixed {
+	if f < 0 {
+		return Fixed(f*float64(FixedOne) - 0.5)
+	}
+	return Fixed(f*float64(FixedOne) + 0.5)
+}
+
+// Int returns f truncated towards negative infinity
+func (f Fixed) Int() int {
+	return int(f >> fixedShift)
+}
+
+// Float returns f as a float64
+func (f Fixed) Float() float64 {
+	return float64(f) / float64(FixedOne)
+}
+
+// Mul returns f*g
+func (f Fixed) Mul(g Fixed) Fixed {
+	return Fixed((int64(f) * int64(g)) >> fixedShift)
+}
+
+// Div returns f/g. It panics if g is zero.
+func (f Fixed) Div(g Fixed) Fixed {
+	return Fixed((int64(f) << fixedShift) / int64(g))
+}
+
+func (f Fixed) String() string {
+	return strconv.FormatFloat(f.Float(), 'f', -1, 64)
+}
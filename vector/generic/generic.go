@@ -0,0 +1,97 @@
+// Package generic provides 2D and 3D vectors over any numeric type, so
+// tile code can use integer vectors, offline tools float64 vectors and
+// deterministic simulations the Fixed point type. The float32 Vector of
+// the vector package converts to and from Vec3[float32].
+package generic
+
+import "github.com/dikaeinstein/games-with-go/vector"
+
+// Scalar is the set of types a vector can hold. Fixed is an int32, but
+// the vectors multiply, interpolate and convert it as a fixed point
+// number. Only Fixed itself is detected: a type defined from it, such as
+// type Meters Fixed, is handled as a plain int32.
+type Scalar interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// mul returns a*b, keeping the point of Fixed values in place
+func mul[T Scalar](a, b T) T {
+	if f, ok := any(a).(Fixed); ok {
+		return any(f.Mul(any(b).(Fixed))).(T)
+	}
+	return a * b
+}
+
+// toFloat returns v as a float64
+func toFloat[T Scalar](v T) float64 {
+	if f, ok := any(v).(Fixed); ok {
+		return f.Float()
+	}
+	return float64(v)
+}
+
+// fromFloat returns f as a T, truncated towards zero for integers
+func fromFloat[T Scalar](f float64) T {
+	var zero T
+	if _, ok := any(zero).(Fixed); ok {
+		return any(FixedFromFloat(f)).(T)
+	}
+	return T(f)
+}
+
+// lerp returns a + (b-a)*pct. Fixed values are interpolated in fixed
+// point so the result is the same on every platform.
+func lerp[T Scalar](a, b T, pct float64) T {
+	if fa, ok := any(a).(Fixed); ok {
+		fb := any(b).(Fixed)
+		return any(fa + (fb - fa).Mul(FixedFromFloat(pct))).(T)
+	}
+	fa := toFloat(a)
+	return fromFloat[T](fa + float64((toFloat(b)-fa)*pct))
+}
+
+func minScalar[T Scalar](a, b T) T {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxScalar[T Scalar](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// FromVector returns v as a Vec3[float32]
+func FromVector(v vector.Vector) Vec3[float32] {
+	return Vec3[float32]{v.X, v.Y, v.Z}
+}
+
+// ToVector returns v as a float32 Vector
+func ToVector[T Scalar](v Vec3[T]) vector.Vector {
+	return vector.Vector{X: float32(toFloat(v.X)), Y: float32(toFloat(v.Y)), Z: float32(toFloat(v.Z))}
+}
+
+// FromVec2 returns v as a Vec2[float32]
+func FromVec2(v vector.Vec2) Vec2[float32] {
+	return Vec2[float32]{v.X, v.Y}
+}
+
+// ToVec2 returns v as a float32 vector.Vec2
+func ToVec2[T Scalar](v Vec2[T]) vector.Vec2 {
+	return vector.Vec2{X: float32(toFloat(v.X)), Y: float32(toFloat(v.Y))}
+}
+
+// Convert2 returns v with its components converted to U, truncating
+// towards zero when U is an integer
+func Convert2[U, T Scalar](v Vec2[T]) Vec2[U] {
+	return Vec2[U]{fromFloat[U](toFloat(v.X)), fromFloat[U](toFloat(v.Y))}
+}
+
+// Convert3 returns v with its components converted to U, truncating
+// towards zero when U is an integer
+func Convert3[U, T Scalar](v Vec3[T]) Vec3[U] {
+	return Vec3[U]{fromFloat[U](toFloat(v.X)), fromFloat[U](toFloat(v.Y)), fromFloat[U](toFloat(v.Z))}
+}
@@ -0,0 +1,84 @@
+package generic
+
+import "testing"
+
+func TestFixed(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want Fixed
+	}{
+		{"FixedFromInt(3)", FixedFromInt(3), 3 << 16},
+		{"FixedFromInt(-2)", FixedFromInt(-2), -2 << 16},
+		{"FixedFromFloat(1.5)", FixedFromFloat(1.5), 0x18000},
+		{"FixedFromFloat(-1.5)", FixedFromFloat(-1.5), -0x18000},
+		// 1/65536 is the smallest step, halfway rounds away from zero
+		{"FixedFromFloat(0.5/65536)", FixedFromFloat(0.5 / 65536), 1},
+		{"FixedFromFloat(-0.5/65536)", FixedFromFloat(-0.5 / 65536), -1},
+		{"1.5 * 2.25", FixedFromFloat(1.5).Mul(FixedFromFloat(2.25)), FixedFromFloat(3.375)},
+		{"-1.5 * 2", FixedFromFloat(-1.5).Mul(FixedFromInt(2)), FixedFromInt(-3)},
+		{"7 / 2", FixedFromInt(7).Div(FixedFromInt(2)), FixedFromFloat(3.5)},
+		{"-1 / 4", FixedFromInt(-1).Div(FixedFromInt(4)), FixedFromFloat(-0.25)},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if got := FixedFromFloat(-1.5).Int(); got != -2 {
+		t.Errorf("FixedFromFloat(-1.5).Int() = %d, want -2", got)
+	}
+	if got := FixedFromFloat(-1.25).String(); got != "-1.25" {
+		t.Errorf("FixedFromFloat(-1.25).String() = %q, want \"-1.25\"", got)
+	}
+}
+
+func TestFixedVectors(t *testing.T) {
+	v := Vec2[Fixed]{FixedFromInt(1), FixedFromFloat(-2.5)}
+	u := Vec2[Fixed]{FixedFromFloat(0.5), FixedFromInt(4)}
+
+	if got, want := v.Dot(u), FixedFromFloat(-9.5); got != want {
+		t.Errorf("Dot = %v, want %v", got, want)
+	}
+	if got, want := v.Cross(u), FixedFromFloat(5.25); got != want {
+		t.Errorf("Cross = %v, want %v", got, want)
+	}
+
+	// the lerp is done in fixed point, so its result is exact
+	want := Vec2[Fixed]{FixedFromFloat(0.875), FixedFromFloat(-0.875)}
+	if got := v.Lerp(u, 0.25); got != want {
+		t.Errorf("Lerp = %v, want %v", got, want)
+	}
+	if got := v.Lerp(u, 0); got != v {
+		t.Errorf("Lerp at 0 = %v, want %v", got, v)
+	}
+	if got := v.Lerp(u, 1); got != u {
+		t.Errorf("Lerp at 1 = %v, want %v", got, u)
+	}
+
+	w := Vec3[Fixed]{FixedFromInt(3), FixedFromInt(4), 0}
+	if got := w.Length(); got != 5 {
+		t.Errorf("Length = %v, want 5", got)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	if got, want := Convert2[int32](Vec2[float64]{1.9, -1.9}), (Vec2[int32]{1, -1}); got != want {
+		t.Errorf("Convert2[int32] = %v, want %v", got, want)
+	}
+	if got, want := Convert2[Fixed](Vec2[int]{3, -2}), (Vec2[Fixed]{FixedFromInt(3), FixedFromInt(-2)}); got != want {
+		t.Errorf("Convert2[Fixed] = %v, want %v", got, want)
+	}
+	if got, want := Convert2[float32](Vec2[Fixed]{FixedFromFloat(0.25), FixedFromFloat(-3.5)}), (Vec2[float32]{0.25, -3.5}); got != want {
+		t.Errorf("Convert2[float32] = %v, want %v", got, want)
+	}
+
+	v := Vec3[float32]{2.75, -0.5, 100}
+	if got, want := Convert3[int16](v), (Vec3[int16]{2, 0, 100}); got != want {
+		t.Errorf("Convert3[int16] = %v, want %v", got, want)
+	}
+	if got := Convert3[float32](Convert3[Fixed](v)); got != v {
+		t.Errorf("Convert3 through Fixed = %v, want %v", got, v)
+	}
+}
@@ -0,0 +1,65 @@
+package generic
+
+import "math"
+
+// Vec2 represents a 2D vector of T
+type Vec2[T Scalar] struct {
+	X, Y T
+}
+
+// Add returns the sum of v and u
+func (v Vec2[T]) Add(u Vec2[T]) Vec2[T] {
+	return Vec2[T]{v.X + u.X, v.Y + u.Y}
+}
+
+// Sub returns the difference of v and u
+func (v Vec2[T]) Sub(u Vec2[T]) Vec2[T] {
+	return Vec2[T]{v.X - u.X, v.Y - u.Y}
+}
+
+// Scale returns v multiplied by the scalar factor
+func (v Vec2[T]) Scale(factor T) Vec2[T] {
+	return Vec2[T]{mul(v.X, factor), mul(v.Y, factor)}
+}
+
+// Mul returns the component-wise product of v and u
+func (v Vec2[T]) Mul(u Vec2[T]) Vec2[T] {
+	return Vec2[T]{mul(v.X, u.X), mul(v.Y, u.Y)}
+}
+
+// Min returns the component-wise minimum of v and u
+func (v Vec2[T]) Min(u Vec2[T]) Vec2[T] {
+	return Vec2[T]{minScalar(v.X, u.X), minScalar(v.Y, u.Y)}
+}
+
+// Max returns the component-wise maximum of v and u
+func (v Vec2[T]) Max(u Vec2[T]) Vec2[T] {
+	return Vec2[T]{maxScalar(v.X, u.X), maxScalar(v.Y, u.Y)}
+}
+
+// Dot returns the dot product of v and u
+func (v Vec2[T]) Dot(u Vec2[T]) T {
+	return mul(v.X, u.X) + mul(v.Y, u.Y)
+}
+
+// Cross returns the Z component of the cross product of v and u
+func (v Vec2[T]) Cross(u Vec2[T]) T {
+	return mul(v.X, u.Y) - mul(v.Y, u.X)
+}
+
+// LengthSquared returns the square of the length of the vector
+func (v Vec2[T]) LengthSquared() T {
+	return v.Dot(v)
+}
+
+// Length returns the length/magnitude of the vector
+func (v Vec2[T]) Length() float64 {
+	x, y := toFloat(v.X), toFloat(v.Y)
+	return math.Sqrt(float64(x*x) + float64(y*y))
+}
+
+// Lerp is the linear interpolation between v and u, truncated towards
+// zero for integer vectors
+func (v Vec2[T]) Lerp(u Vec2[T], pct float64) Vec2[T] {
+	return Vec2[T]{lerp(v.X, u.X, pct), lerp(v.Y, u.Y, pct)}
+}
@@ -0,0 +1,74 @@
+package generic
+
+import "math"
+
+// Vec3 represents a 3D vector of T
+type Vec3[T Scalar] struct {
+	X, Y, Z T
+}
+
+// Add returns the sum of v and u
+func (v Vec3[T]) Add(u Vec3[T]) Vec3[T] {
+	return Vec3[T]{v.X + u.X, v.Y + u.Y, v.Z + u.Z}
+}
+
+// Sub returns the difference of v and u
+func (v Vec3[T]) Sub(u Vec3[T]) Vec3[T] {
+	return Vec3[T]{v.X - u.X, v.Y - u.Y, v.Z - u.Z}
+}
+
+// Scale returns v multiplied by the scalar factor
+func (v Vec3[T]) Scale(factor T) Vec3[T] {
+	return Vec3[T]{mul(v.X, factor), mul(v.Y, factor), mul(v.Z, factor)}
+}
+
+// Mul returns the component-wise product of v and u
+func (v Vec3[T]) Mul(u Vec3[T]) Vec3[T] {
+	return Vec3[T]{mul(v.X, u.X), mul(v.Y, u.Y), mul(v.Z, u.Z)}
+}
+
+// Min returns the component-wise minimum of v and u
+func (v Vec3[T]) Min(u Vec3[T]) Vec3[T] {
+	return Vec3[T]{minScalar(v.X, u.X), minScalar(v.Y, u.Y), minScalar(v.Z, u.Z)}
+}
+
+// Max returns the component-wise maximum of v and u
+func (v Vec3[T]) Max(u Vec3[T]) Vec3[T] {
+	return Vec3[T]{maxScalar(v.X, u.X), maxScalar(v.Y, u.Y), maxScalar(v.Z, u.Z)}
+}
+
+// Dot returns the dot product of v and u
+func (v Vec3[T]) Dot(u Vec3[T]) T {
+	return mul(v.X, u.X) + mul(v.Y, u.Y) + mul(v.Z, u.Z)
+}
+
+// Cross returns the cross product of v and u
+func (v Vec3[T]) Cross(u Vec3[T]) Vec3[T] {
+	return Vec3[T]{
+		X: mul(v.Y, u.Z) - mul(v.Z, u.Y),
+		Y: mul(v.Z, u.X) - mul(v.X, u.Z),
+		Z: mul(v.X, u.Y) - mul(v.Y, u.X),
+	}
+}
+
+// LengthSquared returns the square of the length of the vector
+func (v Vec3[T]) LengthSquared() T {
+	return v.Dot(v)
+}
+
+// Length returns the length/magnitude of the vector
+func (v Vec3[T]) Length() float64 {
+	x, y, z := toFloat(v.X), toFloat(v.Y), toFloat(v.Z)
+	return math.Sqrt(float64(x*x) + float64(y*y) + float64(z*z))
+}
+
+// Lerp is the linear interpolation between v and u, truncated towards
+// zero for integer vectors
+func (v Vec3[T]) Lerp(u Vec3[T], pct float64) Vec3[T] {
+	return Vec3[T]{lerp(v.X, u.X, pct), lerp(v.Y, u.Y, pct), lerp(v.Z, u.Z, pct)}
+}
+
+// XY returns the X and Y components of v
+func (v Vec3[T]) XY() Vec2[T] {
+	return Vec2[T]{v.X, v.Y}
+}
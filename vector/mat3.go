@@ -0,0 +1,121 @@
+package vector
+
+import "math"
+
+// Mat3 is a 3x3 matrix stored column by column. It transforms 2D points in
+// homogeneous coordinates, so it can hold any 2D affine transform.
+type Mat3 [9]float32
+
+// Identity3 returns the identity matrix
+func Identity3() Mat3 {
+	return Mat3{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// Translation3 returns the matrix moving points by v
+func Translation3(v Vec2) Mat3 {
+	return Mat3{
+		1, 0, 0,
+		0, 1, 0,
+		v.X, v.Y, 1,
+	}
+}
+
+// Rotation3 returns the matrix rotating points counter-clockwise by angle
+// radians around the origin
+func Rotation3(angle float32) Mat3 {
+	s, c := math.Sincos(float64(angle))
+	sin, cos := float32(s), float32(c)
+	return Mat3{
+		cos, sin, 0,
+		-sin, cos, 0,
+		0, 0, 1,
+	}
+}
+
+// Scaling3 returns the matrix scaling points by v around the origin
+func Scaling3(v Vec2) Mat3 {
+	return Mat3{
+		v.X, 0, 0,
+		0, v.Y, 0,
+		0, 0, 1,
+	}
+}
+
+// At returns the element at the given row and column
+func (m Mat3) At(row, col int) float32 {
+	return m[col*3+row]
+}
+
+// Mul returns the product m * n, which applies n first and then m
+func (m Mat3) Mul(n Mat3) Mat3 {
+	var r Mat3
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			var sum float32
+			for k := 0; k < 3; k++ {
+				sum += m[k*3+row] * n[col*3+k]
+			}
+			r[col*3+row] = sum
+		}
+	}
+	return r
+}
+
+// MulVector returns the product of m and the column vector v
+func (m Mat3) MulVector(v Vector) Vector {
+	return Vector{
+		X: m[0]*v.X + m[3]*v.Y + m[6]*v.Z,
+		Y: m[1]*v.X + m[4]*v.Y + m[7]*v.Z,
+		Z: m[2]*v.X + m[5]*v.Y + m[8]*v.Z,
+	}
+}
+
+// MulPoint transforms the 2D point p, including the translation of m
+func (m Mat3) MulPoint(p Vec2) Vec2 {
+	return m.MulVector(p.Vec3(1)).XY()
+}
+
+// MulDir transforms the 2D direction d, ignoring the translation of m
+func (m Mat3) MulDir(d Vec2) Vec2 {
+	return m.MulVector(d.Vec3(0)).XY()
+}
+
+// Transpose returns m with its rows and columns swapped
+func (m Mat3) Transpose() Mat3 {
+	return Mat3{
+		m[0], m[3], m[6],
+		m[1], m[4], m[7],
+		m[2], m[5], m[8],
+	}
+}
+
+// Determinant returns the determinant of m
+func (m Mat3) Determinant() float32 {
+	return m[0]*(m[4]*m[8]-m[7]*m[5]) -
+		m[3]*(m[1]*m[8]-m[7]*m[2]) +
+		m[6]*(m[1]*m[5]-m[4]*m[2])
+}
+
+// Inverse returns the inverse of m. It returns false if m is singular.
+func (m Mat3) Inverse() (Mat3, bool) {
+	det := m.Determinant()
+	if det == 0 {
+		return Mat3{}, false
+	}
+	inv := 1 / det
+	return Mat3{
+		(m[4]*m[8] - m[7]*m[5]) * inv,
+		(m[7]*m[2] - m[1]*m[8]) * inv,
+		(m[1]*m[5] - m[4]*m[2]) * inv,
+		(m[6]*m[5] - m[3]*m[8]) * inv,
+		(m[0]*m[8] - m[6]*m[2]) * inv,
+		(m[3]*m[2] - m[0]*m[5]) * inv,
+		(m[3]*m[7] - m[6]*m[4]) * inv,
+		(m[6]*m[1] - m[0]*m[7]) * inv,
+		(m[0]*m[4] - m[3]*m[1]) * inv,
+	}, true
+}
@@ -0,0 +1,202 @@
+package vector
+
+import "math"
+
+// Mat4 is a 4x4 matrix stored column by column, the layout OpenGL expects.
+// It transforms 3D points in homogeneous coordinates, so it can hold any
+// 3D affine transform or a projection.
+type Mat4 [16]float32
+
+// Identity4 returns the identity matrix
+func Identity4() Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Translation4 returns the matrix moving points by v
+func Translation4(v Vector) Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		v.X, v.Y, v.Z, 1,
+	}
+}
+
+// Scaling4 returns the matrix scaling points by v around the origin
+func Scaling4(v Vector) Mat4 {
+	return Mat4{
+		v.X, 0, 0, 0,
+		0, v.Y, 0, 0,
+		0, 0, v.Z, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Rotation4 returns the matrix rotating points by angle radians around
+// axis, counter-clockwise when looking down the axis towards the origin
+func Rotation4(axis Vector, angle float32) Mat4 {
+	return AxisAngle(axis, angle).Mat4()
+}
+
+// LookAt returns the view matrix of a camera at eye looking at center,
+// with up pointing roughly upwards. The camera looks down its -Z axis.
+func LookAt(eye, center, up Vector) Mat4 {
+	f := center.Sub(eye).Normalize()
+	s := f.Cross(up).Normalize()
+	u := s.Cross(f)
+
+	return Mat4{
+		s.X, u.X, -f.X, 0,
+		s.Y, u.Y, -f.Y, 0,
+		s.Z, u.Z, -f.Z, 0,
+		-s.Dot(eye), -u.Dot(eye), f.Dot(eye), 1,
+	}
+}
+
+// Perspective returns the projection matrix of a camera with the vertical
+// field of view fovy in radians and the aspect ratio width/height. Points
+// between the near and far planes end up in the [-1,1] cube after the
+// division by W.
+func Perspective(fovy, aspect, near, far float32) Mat4 {
+	f := float32(1 / math.Tan(float64(fovy)/2))
+	nf := 1 / (near - far)
+
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, (far + near) * nf, -1,
+		0, 0, 2 * far * near * nf, 0,
+	}
+}
+
+// Orthographic returns the projection matrix mapping the given box to the
+// [-1,1] cube without perspective
+func Orthographic(left, right, bottom, top, near, far float32) Mat4 {
+	rl := 1 / (right - left)
+	tb := 1 / (top - bottom)
+	fn := 1 / (far - near)
+
+	return Mat4{
+		2 * rl, 0, 0, 0,
+		0, 2 * tb, 0, 0,
+		0, 0, -2 * fn, 0,
+		-(right + left) * rl, -(top + bottom) * tb, -(far + near) * fn, 1,
+	}
+}
+
+// At returns the element at the given row and column
+func (m Mat4) At(row, col int) float32 {
+	return m[col*4+row]
+}
+
+// Mul returns the product m * n, which applies n first and then m
+func (m Mat4) Mul(n Mat4) Mat4 {
+	var r Mat4
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			var sum float32
+			for k := 0; k < 4; k++ {
+				sum += m[k*4+row] * n[col*4+k]
+			}
+			r[col*4+row] = sum
+		}
+	}
+	return r
+}
+
+// MulVec4 returns the product of m and the column vector v
+func (m Mat4) MulVec4(v Vec4) Vec4 {
+	return Vec4{
+		X: m[0]*v.X + m[4]*v.Y + m[8]*v.Z + m[12]*v.W,
+		Y: m[1]*v.X + m[5]*v.Y + m[9]*v.Z + m[13]*v.W,
+		Z: m[2]*v.X + m[6]*v.Y + m[10]*v.Z + m[14]*v.W,
+		W: m[3]*v.X + m[7]*v.Y + m[11]*v.Z + m[15]*v.W,
+	}
+}
+
+// MulPoint transforms the point p, including the translation of m and the
+// division by W of a projection
+func (m Mat4) MulPoint(p Vector) Vector {
+	return m.MulVec4(p.Vec4(1)).Homogenize()
+}
+
+// MulDir transforms the direction d, ignoring the translation of m
+func (m Mat4) MulDir(d Vector) Vector {
+	return m.MulVec4(d.Vec4(0)).XYZ()
+}
+
+// Transpose returns m with its rows and columns swapped
+func (m Mat4) Transpose() Mat4 {
+	var r Mat4
+	for col := 0; col < 4; col++ {
+		for row := 0; row < 4; row++ {
+			r[row*4+col] = m[col*4+row]
+		}
+	}
+	return r
+}
+
+// cofactors returns the transposed cofactor matrix of m, that is the
+// inverse of m times its determinant
+func (m Mat4) cofactors() Mat4 {
+	var inv Mat4
+	inv[0] = m[5]*m[10]*m[15] - m[5]*m[11]*m[14] - m[9]*m[6]*m[15] +
+		m[9]*m[7]*m[14] + m[13]*m[6]*m[11] - m[13]*m[7]*m[10]
+	inv[4] = -m[4]*m[10]*m[15] + m[4]*m[11]*m[14] + m[8]*m[6]*m[15] -
+		m[8]*m[7]*m[14] - m[12]*m[6]*m[11] + m[12]*m[7]*m[10]
+	inv[8] = m[4]*m[9]*m[15] - m[4]*m[11]*m[13] - m[8]*m[5]*m[15] +
+		m[8]*m[7]*m[13] + m[12]*m[5]*m[11] - m[12]*m[7]*m[9]
+	inv[12] = -m[4]*m[9]*m[14] + m[4]*m[10]*m[13] + m[8]*m[5]*m[14] -
+		m[8]*m[6]*m[13] - m[12]*m[5]*m[10] + m[12]*m[6]*m[9]
+	inv[1] = -m[1]*m[10]*m[15] + m[1]*m[11]*m[14] + m[9]*m[2]*m[15] -
+		m[9]*m[3]*m[14] - m[13]*m[2]*m[11] + m[13]*m[3]*m[10]
+	inv[5] = m[0]*m[10]*m[15] - m[0]*m[11]*m[14] - m[8]*m[2]*m[15] +
+		m[8]*m[3]*m[14] + m[12]*m[2]*m[11] - m[12]*m[3]*m[10]
+	inv[9] = -m[0]*m[9]*m[15] + m[0]*m[11]*m[13] + m[8]*m[1]*m[15] -
+		m[8]*m[3]*m[13] - m[12]*m[1]*m[11] + m[12]*m[3]*m[9]
+	inv[13] = m[0]*m[9]*m[14] - m[0]*m[10]*m[13] - m[8]*m[1]*m[14] +
+		m[8]*m[2]*m[13] + m[12]*m[1]*m[10] - m[12]*m[2]*m[9]
+	inv[2] = m[1]*m[6]*m[15] - m[1]*m[7]*m[14] - m[5]*m[2]*m[15] +
+		m[5]*m[3]*m[14] + m[13]*m[2]*m[7] - m[13]*m[3]*m[6]
+	inv[6] = -m[0]*m[6]*m[15] + m[0]*m[7]*m[14] + m[4]*m[2]*m[15] -
+		m[4]*m[3]*m[14] - m[12]*m[2]*m[7] + m[12]*m[3]*m[6]
+	inv[10] = m[0]*m[5]*m[15] - m[0]*m[7]*m[13] - m[4]*m[1]*m[15] +
+		m[4]*m[3]*m[13] + m[12]*m[1]*m[7] - m[12]*m[3]*m[5]
+	inv[14] = -m[0]*m[5]*m[14] + m[0]*m[6]*m[13] + m[4]*m[1]*m[14] -
+		m[4]*m[2]*m[13] - m[12]*m[1]*m[6] + m[12]*m[2]*m[5]
+	inv[3] = -m[1]*m[6]*m[11] + m[1]*m[7]*m[10] + m[5]*m[2]*m[11] -
+		m[5]*m[3]*m[10] - m[9]*m[2]*m[7] + m[9]*m[3]*m[6]
+	inv[7] = m[0]*m[6]*m[11] - m[0]*m[7]*m[10] - m[4]*m[2]*m[11] +
+		m[4]*m[3]*m[10] + m[8]*m[2]*m[7] - m[8]*m[3]*m[6]
+	inv[11] = -m[0]*m[5]*m[11] + m[0]*m[7]*m[9] + m[4]*m[1]*m[11] -
+		m[4]*m[3]*m[9] - m[8]*m[1]*m[7] + m[8]*m[3]*m[5]
+	inv[15] = m[0]*m[5]*m[10] - m[0]*m[6]*m[9] - m[4]*m[1]*m[10] +
+		m[4]*m[2]*m[9] + m[8]*m[1]*m[6] - m[8]*m[2]*m[5]
+	return inv
+}
+
+// Determinant returns the determinant of m
+func (m Mat4) Determinant() float32 {
+	c := m.cofactors()
+	return m[0]*c[0] + m[1]*c[4] + m[2]*c[8] + m[3]*c[12]
+}
+
+// Inverse returns the inverse of m. It returns false if m is singular.
+func (m Mat4) Inverse() (Mat4, bool) {
+	c := m.cofactors()
+	det := m[0]*c[0] + m[1]*c[4] + m[2]*c[8] + m[3]*c[12]
+	if det == 0 {
+		return Mat4{}, false
+	}
+
+	inv := 1 / det
+	for i := range c {
+		c[i] *= inv
+	}
+	return c, true
+}
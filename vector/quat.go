@@ -0,0 +1,147 @@
+package vector
+
+import "math"
+
+// Quat is a quaternion X*i + Y*j + Z*k + W. Unit quaternions represent
+// rotations in 3D without the gimbal lock of Euler angles.
+type Quat struct {
+	X, Y, Z, W float32
+}
+
+// QuatIdentity returns the quaternion of no rotation
+func QuatIdentity() Quat {
+	return Quat{W: 1}
+}
+
+// AxisAngle returns the rotation by angle radians around axis. The axis
+// does not need to be normalized.
+func AxisAngle(axis Vector, angle float32) Quat {
+	s, c := math.Sincos(float64(angle) / 2)
+	v := axis.Normalize().Scale(float32(s))
+	return Quat{v.X, v.Y, v.Z, float32(c)}
+}
+
+// AxisAngle returns the axis and the angle in radians of the rotation q.
+// The identity rotation returns the X axis and an angle of 0.
+func (q Quat) AxisAngle() (Vector, float32) {
+	q = q.Normalize()
+	if q.W < 0 {
+		q = q.Scale(-1)
+	}
+
+	angle := 2 * float32(math.Acos(float64(q.W)))
+	s := float32(math.Sqrt(float64(1 - q.W*q.W)))
+	if s < 1e-6 {
+		return Vector{X: 1}, angle
+	}
+	return Vector{q.X / s, q.Y / s, q.Z / s}, angle
+}
+
+// Vector returns the X, Y and Z components of q
+func (q Quat) Vector() Vector {
+	return Vector{q.X, q.Y, q.Z}
+}
+
+// Length returns the norm of q
+func (q Quat) Length() float32 {
+	return float32(math.Sqrt(float64(q.Dot(q))))
+}
+
+// Dot returns the dot product of q and r as 4D vectors
+func (q Quat) Dot(r Quat) float32 {
+	return q.X*r.X + q.Y*r.Y + q.Z*r.Z + q.W*r.W
+}
+
+// Scale returns every component of q multiplied by factor
+func (q Quat) Scale(factor float32) Quat {
+	return Quat{q.X * factor, q.Y * factor, q.Z * factor, q.W * factor}
+}
+
+// Normalize returns q with a norm of 1. The zero quaternion returns the
+// identity.
+func (q Quat) Normalize() Quat {
+	len := q.Length()
+	if len == 0 {
+		return QuatIdentity()
+	}
+	return q.Scale(1 / len)
+}
+
+// Conjugate returns q with its vector part negated, which is the inverse
+// of a unit quaternion
+func (q Quat) Conjugate() Quat {
+	return Quat{-q.X, -q.Y, -q.Z, q.W}
+}
+
+// Inverse returns the quaternion undoing q. The zero quaternion returns
+// itself.
+func (q Quat) Inverse() Quat {
+	lenSq := q.Dot(q)
+	if lenSq == 0 {
+		return q
+	}
+	return q.Conjugate().Scale(1 / lenSq)
+}
+
+// Mul returns the Hamilton product q * r, which rotates by r first and
+// then by q
+func (q Quat) Mul(r Quat) Quat {
+	return Quat{
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+	}
+}
+
+// Rotate returns v rotated by the unit quaternion q
+func (q Quat) Rotate(v Vector) Vector {
+	// v + 2w(u x v) + 2u x (u x v), the expansion of q v q*
+	u := q.Vector()
+	t := u.Cross(v).Scale(2)
+	return v.Add(t.Scale(q.W)).Add(u.Cross(t))
+}
+
+// Mat4 returns the rotation matrix of the unit quaternion q
+func (q Quat) Mat4() Mat4 {
+	x, y, z, w := q.X, q.Y, q.Z, q.W
+	return Mat4{
+		1 - 2*(y*y+z*z), 2 * (x*y + z*w), 2 * (x*z - y*w), 0,
+		2 * (x*y - z*w), 1 - 2*(x*x+z*z), 2 * (y*z + x*w), 0,
+		2 * (x*z + y*w), 2 * (y*z - x*w), 1 - 2*(x*x+y*y), 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Slerp is the spherical linear interpolation between the unit
+// quaternions q and r, which rotates at a constant speed along the
+// shortest arc
+func Slerp(q, r Quat, pct float32) Quat {
+	cos := q.Dot(r)
+	// q and -q are the same rotation, take the one on the shortest arc
+	if cos < 0 {
+		r = r.Scale(-1)
+		cos = -cos
+	}
+
+	// nearly the same rotation, lerp to avoid dividing by sin(0)
+	if cos > 0.9995 {
+		return Quat{
+			q.X + (r.X-q.X)*pct,
+			q.Y + (r.Y-q.Y)*pct,
+			q.Z + (r.Z-q.Z)*pct,
+			q.W + (r.W-q.W)*pct,
+		}.Normalize()
+	}
+
+	theta := math.Acos(float64(cos))
+	sin := math.Sin(theta)
+	a := float32(math.Sin((1-float64(pct))*theta) / sin)
+	b := float32(math.Sin(float64(pct)*theta) / sin)
+	return Quat{
+		a*q.X + b*r.X,
+		a*q.Y + b*r.Y,
+		a*q.Z + b*r.Z,
+		a*q.W + b*r.W,
+	}
+}
@@ -0,0 +1,125 @@
+package vector
+
+import "math"
+
+// Vec2 represents a 2D vector
+type Vec2 struct {
+	X, Y float32
+}
+
+// Length returns the length/magnitude of the vector
+func (v Vec2) Length() float32 {
+	return float32(math.Sqrt(float64(v.X*v.X + v.Y*v.Y)))
+}
+
+// LengthSquared returns the square of the length of the vector, which
+// avoids a square root when only comparing lengths
+func (v Vec2) LengthSquared() float32 {
+	return v.X*v.X + v.Y*v.Y
+}
+
+// Add returns the sum of v and u
+func (v Vec2) Add(u Vec2) Vec2 {
+	return Vec2{v.X + u.X, v.Y + u.Y}
+}
+
+// Sub returns the difference of v and u
+func (v Vec2) Sub(u Vec2) Vec2 {
+	return Vec2{v.X - u.X, v.Y - u.Y}
+}
+
+// Scale returns v multiplied by the scalar factor
+func (v Vec2) Scale(factor float32) Vec2 {
+	return Vec2{v.X * factor, v.Y * factor}
+}
+
+// Mul returns the component-wise product of v and u
+func (v Vec2) Mul(u Vec2) Vec2 {
+	return Vec2{v.X * u.X, v.Y * u.Y}
+}
+
+// Min returns the component-wise minimum of v and u
+func (v Vec2) Min(u Vec2) Vec2 {
+	return Vec2{min32(v.X, u.X), min32(v.Y, u.Y)}
+}
+
+// Max returns the component-wise maximum of v and u
+func (v Vec2) Max(u Vec2) Vec2 {
+	return Vec2{max32(v.X, u.X), max32(v.Y, u.Y)}
+}
+
+// Dot returns the dot product of v and u
+func (v Vec2) Dot(u Vec2) float32 {
+	return v.X*u.X + v.Y*u.Y
+}
+
+// Cross returns the Z component of the cross product of v and u, which is
+// positive when u is counter-clockwise from v
+func (v Vec2) Cross(u Vec2) float32 {
+	return v.X*u.Y - v.Y*u.X
+}
+
+// Perp returns v rotated a quarter turn counter-clockwise
+func (v Vec2) Perp() Vec2 {
+	return Vec2{-v.Y, v.X}
+}
+
+// Distance returns the distance between v and u
+func (v Vec2) Distance(u Vec2) float32 {
+	return v.Sub(u).Length()
+}
+
+// DistanceSquared returns the square of the distance between v and u
+func (v Vec2) DistanceSquared(u Vec2) float32 {
+	return v.Sub(u).LengthSquared()
+}
+
+// Normalize returns the unit vector with the direction of v. The zero
+// vector is returned unchanged.
+func (v Vec2) Normalize() Vec2 {
+	len := v.Length()
+	if len == 0 {
+		return v
+	}
+	return v.Scale(1 / len)
+}
+
+// Lerp is the linear interpolation between v and u
+func (v Vec2) Lerp(u Vec2, pct float32) Vec2 {
+	return v.Add(u.Sub(v).Scale(pct))
+}
+
+// Reflect returns v bounced off a surface with the unit normal n
+func (v Vec2) Reflect(n Vec2) Vec2 {
+	return v.Sub(n.Scale(2 * v.Dot(n)))
+}
+
+// Project returns the projection of v onto u, or the zero vector when u
+// is zero
+func (v Vec2) Project(u Vec2) Vec2 {
+	lenSq := u.LengthSquared()
+	if lenSq == 0 {
+		return Vec2{}
+	}
+	return u.Scale(v.Dot(u) / lenSq)
+}
+
+// Angle returns the angle between v and u in radians, in [0, Pi]. It is
+// 0 when either vector is zero.
+func (v Vec2) Angle(u Vec2) float32 {
+	return angle(v.Dot(u), v.Length()*u.Length())
+}
+
+// ClampLength returns v shortened to max when it is longer
+func (v Vec2) ClampLength(max float32) Vec2 {
+	len := v.Length()
+	if len <= max || len == 0 {
+		return v
+	}
+	return v.Scale(max / len)
+}
+
+// Vec3 returns v extended with the given Z component
+func (v Vec2) Vec3(z float32) Vector {
+	return Vector{v.X, v.Y, z}
+}
@@ -0,0 +1,123 @@
+package vector
+
+import "math"
+
+// Vec4 represents a 4D vector, such as a point in homogeneous coordinates
+type Vec4 struct {
+	X, Y, Z, W float32
+}
+
+// Length returns the length/magnitude of the vector
+func (v Vec4) Length() float32 {
+	return float32(math.Sqrt(float64(v.LengthSquared())))
+}
+
+// LengthSquared returns the square of the length of the vector, which
+// avoids a square root when only comparing lengths
+func (v Vec4) LengthSquared() float32 {
+	return v.X*v.X + v.Y*v.Y + v.Z*v.Z + v.W*v.W
+}
+
+// Add returns the sum of v and u
+func (v Vec4) Add(u Vec4) Vec4 {
+	return Vec4{v.X + u.X, v.Y + u.Y, v.Z + u.Z, v.W + u.W}
+}
+
+// Sub returns the difference of v and u
+func (v Vec4) Sub(u Vec4) Vec4 {
+	return Vec4{v.X - u.X, v.Y - u.Y, v.Z - u.Z, v.W - u.W}
+}
+
+// Scale returns v multiplied by the scalar factor
+func (v Vec4) Scale(factor float32) Vec4 {
+	return Vec4{v.X * factor, v.Y * factor, v.Z * factor, v.W * factor}
+}
+
+// Mul returns the component-wise product of v and u
+func (v Vec4) Mul(u Vec4) Vec4 {
+	return Vec4{v.X * u.X, v.Y * u.Y, v.Z * u.Z, v.W * u.W}
+}
+
+// Min returns the component-wise minimum of v and u
+func (v Vec4) Min(u Vec4) Vec4 {
+	return Vec4{min32(v.X, u.X), min32(v.Y, u.Y), min32(v.Z, u.Z), min32(v.W, u.W)}
+}
+
+// Max returns the component-wise maximum of v and u
+func (v Vec4) Max(u Vec4) Vec4 {
+	return Vec4{max32(v.X, u.X), max32(v.Y, u.Y), max32(v.Z, u.Z), max32(v.W, u.W)}
+}
+
+// Dot returns the dot product of v and u
+func (v Vec4) Dot(u Vec4) float32 {
+	return v.X*u.X + v.Y*u.Y + v.Z*u.Z + v.W*u.W
+}
+
+// Distance returns the distance between v and u
+func (v Vec4) Distance(u Vec4) float32 {
+	return v.Sub(u).Length()
+}
+
+// DistanceSquared returns the square of the distance between v and u
+func (v Vec4) DistanceSquared(u Vec4) float32 {
+	return v.Sub(u).LengthSquared()
+}
+
+// Normalize returns the unit vector with the direction of v. The zero
+// vector is returned unchanged.
+func (v Vec4) Normalize() Vec4 {
+	len := v.Length()
+	if len == 0 {
+		return v
+	}
+	return v.Scale(1 / len)
+}
+
+// Lerp is the linear interpolation between v and u
+func (v Vec4) Lerp(u Vec4, pct float32) Vec4 {
+	return v.Add(u.Sub(v).Scale(pct))
+}
+
+// Reflect returns v bounced off a surface with the unit normal n
+func (v Vec4) Reflect(n Vec4) Vec4 {
+	return v.Sub(n.Scale(2 * v.Dot(n)))
+}
+
+// Project returns the projection of v onto u, or the zero vector when u
+// is zero
+func (v Vec4) Project(u Vec4) Vec4 {
+	lenSq := u.LengthSquared()
+	if lenSq == 0 {
+		return Vec4{}
+	}
+	return u.Scale(v.Dot(u) / lenSq)
+}
+
+// Angle returns the angle between v and u in radians, in [0, Pi]. It is
+// 0 when either vector is zero.
+func (v Vec4) Angle(u Vec4) float32 {
+	return angle(v.Dot(u), v.Length()*u.Length())
+}
+
+// ClampLength returns v shortened to max when it is longer
+func (v Vec4) ClampLength(max float32) Vec4 {
+	len := v.Length()
+	if len <= max || len == 0 {
+		return v
+	}
+	return v.Scale(max / len)
+}
+
+// XYZ returns the X, Y and Z components of v
+func (v Vec4) XYZ() Vector {
+	return Vector{v.X, v.Y, v.Z}
+}
+
+// Homogenize returns the 3D point of the homogeneous coordinates v, that
+// is X, Y and Z divided by W. A zero W is treated as 1.
+func (v Vec4) Homogenize() Vector {
+	if v.W == 0 {
+		return v.XYZ()
+	}
+	return v.XYZ().Scale(1 / v.W)
+}
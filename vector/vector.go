@@ -1,3 +1,4 @@
+// Package vector provides 2D, 3D and 4D float32 vectors
 package vector
 
 import "math"
@@ -12,48 +13,188 @@ func (v Vector) Length() float32 {
 	return float32(math.Sqrt(float64(v.X*v.X + v.Y*v.Y + v.Z*v.Z)))
 }
 
-// Add adds the two given vectors
-func Add(v1, v2 Vector) Vector {
+// LengthSquared returns the square of the length of the vector, which
+// avoids a square root when only comparing lengths
+func (v Vector) LengthSquared() float32 {
+	return v.X*v.X + v.Y*v.Y + v.Z*v.Z
+}
+
+// Add returns the sum of v and u
+func (v Vector) Add(u Vector) Vector {
+	return Vector{v.X + u.X, v.Y + u.Y, v.Z + u.Z}
+}
+
+// Sub returns the difference of v and u
+func (v Vector) Sub(u Vector) Vector {
+	return Vector{v.X - u.X, v.Y - u.Y, v.Z - u.Z}
+}
+
+// Scale returns v multiplied by the scalar factor
+func (v Vector) Scale(factor float32) Vector {
+	return Vector{v.X * factor, v.Y * factor, v.Z * factor}
+}
+
+// Mul returns the component-wise product of v and u
+func (v Vector) Mul(u Vector) Vector {
+	return Vector{v.X * u.X, v.Y * u.Y, v.Z * u.Z}
+}
+
+// Min returns the component-wise minimum of v and u
+func (v Vector) Min(u Vector) Vector {
+	return Vector{min32(v.X, u.X), min32(v.Y, u.Y), min32(v.Z, u.Z)}
+}
+
+// Max returns the component-wise maximum of v and u
+func (v Vector) Max(u Vector) Vector {
+	return Vector{max32(v.X, u.X), max32(v.Y, u.Y), max32(v.Z, u.Z)}
+}
+
+// Dot returns the dot product of v and u
+func (v Vector) Dot(u Vector) float32 {
+	return v.X*u.X + v.Y*u.Y + v.Z*u.Z
+}
+
+// Cross returns the cross product of v and u, which is perpendicular to
+// both and follows the right hand rule
+func (v Vector) Cross(u Vector) Vector {
 	return Vector{
-		X: v1.X + v2.X,
-		Y: v1.Y + v2.Y,
-		Z: v1.Z + v2.Z,
+		X: v.Y*u.Z - v.Z*u.Y,
+		Y: v.Z*u.X - v.X*u.Z,
+		Z: v.X*u.Y - v.Y*u.X,
 	}
 }
 
-// Multiply is the dot product of the two given vectors
-func Multiply(v Vector, factor float32) Vector {
-	return Vector{
-		X: v.X * factor,
-		Y: v.Y * factor,
-		Z: v.Z * factor,
+// Distance returns the distance between v and u
+func (v Vector) Distance(u Vector) float32 {
+	return v.Sub(u).Length()
+}
+
+// DistanceSquared returns the square of the distance between v and u
+func (v Vector) DistanceSquared(u Vector) float32 {
+	return v.Sub(u).LengthSquared()
+}
+
+// Normalize returns the unit vector with the direction of v. The zero
+// vector is returned unchanged.
+func (v Vector) Normalize() Vector {
+	len := v.Length()
+	if len == 0 {
+		return v
+	}
+	return v.Scale(1 / len)
+}
+
+// Lerp is the linear interpolation between v and u
+func (v Vector) Lerp(u Vector, pct float32) Vector {
+	return v.Add(u.Sub(v).Scale(pct))
+}
+
+// Reflect returns v bounced off a surface with the unit normal n
+func (v Vector) Reflect(n Vector) Vector {
+	return v.Sub(n.Scale(2 * v.Dot(n)))
+}
+
+// Project returns the projection of v onto u, or the zero vector when u
+// is zero
+func (v Vector) Project(u Vector) Vector {
+	lenSq := u.LengthSquared()
+	if lenSq == 0 {
+		return Vector{}
+	}
+	return u.Scale(v.Dot(u) / lenSq)
+}
+
+// Angle returns the angle between v and u in radians, in [0, Pi]. It is
+// 0 when either vector is zero.
+func (v Vector) Angle(u Vector) float32 {
+	return angle(v.Dot(u), v.Length()*u.Length())
+}
+
+// ClampLength returns v shortened to max when it is longer
+func (v Vector) ClampLength(max float32) Vector {
+	len := v.Length()
+	if len <= max || len == 0 {
+		return v
 	}
+	return v.Scale(max / len)
+}
+
+// XY returns the X and Y components of v
+func (v Vector) XY() Vec2 {
+	return Vec2{v.X, v.Y}
+}
+
+// Vec4 returns v extended with the given W component
+func (v Vector) Vec4(w float32) Vec4 {
+	return Vec4{v.X, v.Y, v.Z, w}
+}
+
+// Add adds the two given vectors
+func Add(v1, v2 Vector) Vector {
+	return v1.Add(v2)
+}
+
+// Sub subtracts v2 from v1
+func Sub(v1, v2 Vector) Vector {
+	return v1.Sub(v2)
+}
+
+// Multiply scales the given vector by factor
+func Multiply(v Vector, factor float32) Vector {
+	return v.Scale(factor)
+}
+
+// Dot is the dot product of the two given vectors
+func Dot(v1, v2 Vector) float32 {
+	return v1.Dot(v2)
+}
+
+// Cross is the cross product of the two given vectors
+func Cross(v1, v2 Vector) Vector {
+	return v1.Cross(v2)
 }
 
 // Distance is the distance between the two vectors
 func Distance(v1, v2 Vector) float32 {
-	dx := v1.X - v2.X
-	dy := v1.Y - v2.Y
-	dz := v1.Z - v1.Z
-
-	return float32(math.Sqrt(float64(dx*dx + dy*dy + dz*dz)))
+	return v1.Distance(v2)
 }
 
-// DistanceSquared is the sum of the square of the given vectors
+// DistanceSquared is the square of the distance between the two vectors
 func DistanceSquared(v1, v2 Vector) float32 {
-	dx := v1.X - v2.X
-	dy := v1.Y - v2.Y
-	dz := v1.Z - v1.Z
-
-	return dx*dx + dy*dy + dz*dz
+	return v1.DistanceSquared(v2)
 }
 
-// Normalize the given vector i.e convert it into a unit vector
+// Normalize the given vector i.e convert it into a unit vector. The zero
+// vector is returned unchanged.
 func Normalize(v Vector) Vector {
-	len := v.Length()
-	return Vector{
-		X: v.X / len,
-		Y: v.Y / len,
-		Z: v.Z / len,
+	return v.Normalize()
+}
+
+// angle returns the angle whose cosine is dot/lengths, clamped against
+// rounding errors
+func angle(dot, lengths float32) float32 {
+	if lengths == 0 {
+		return 0
+	}
+	cos := dot / lengths
+	if cos > 1 {
+		cos = 1
+	} else if cos < -1 {
+		cos = -1
+	}
+	return float32(math.Acos(float64(cos)))
+}
+
+func min32(a, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b float32) float32 {
+	if a > b {
+		return a
 	}
+	return b
 }